@@ -8,44 +8,100 @@ package acconfig
 import (
 	"bufio"
 	"encoding"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"math"
 	"os"
 	"path"
 	"reflect"
 	"strconv"
 	"strings"
 	"time"
-	"unicode"
 )
 
 type conf struct {
 	file   string
-	lineNo int
-	info   map[reflect.Type]fieldInfo
+	line   int
+	col    int
+	start  position   // start of the most recent token
+	tokPos []position // position of each token in the current line
+	depth  int
+	fsys   fs.FS
+	dec    *Decoder
+	state  *readState // shared with included files
+	block  string     // path of the current block, eg. "thing[2]"
+
+	// where this file was included from, innermost first
+	included []Position
 }
 
 type fieldInfo map[string][]int
 
+// readState is shared by a config file, and all of its includes
+type readState struct {
+	errs      ErrorList       // collected errors
+	defaulted map[string]bool // params that have a default value
+	set       map[string]bool // params that have been set by the config
+	blockPos  map[string]position
+	count     map[string]int // number of blocks read into an array
+}
+
 const dEBUG = false
 
 // Read reads a config file into the struct
 func Read(file string, cf interface{}) error {
+	return New().Read(file, cf)
+}
+
+// ReadFS reads a config file from fsys into the struct
+// included files are also read from fsys
+func ReadFS(fsys fs.FS, file string, cf interface{}) error {
+	return New().ReadFS(fsys, file, cf)
+}
+
+// ReadReader reads config from r into the struct
+// name is used in error messages, and to locate included files
+func ReadReader(name string, r io.Reader, cf interface{}) error {
+	return New().ReadReader(name, r, cf)
+}
 
-	debugf("read %s\n", file)
-	f, err := os.Open(file)
+// ReadString reads config from the string into the struct
+func ReadString(name string, s string, cf interface{}) error {
+	return New().ReadString(name, s, cf)
+}
+
+func (c *conf) readFile(cf interface{}) error {
+
+	debugf("read %s\n", c.file)
+	f, err := c.open(c.file)
 	if err != nil {
-		return fmt.Errorf("cannot open '%s': %v", file, err)
+		return fmt.Errorf("cannot open '%s': %v", c.file, err)
 	}
 
 	defer f.Close()
 
-	c := &conf{
-		file:   file,
-		lineNo: 1,
+	return c.read(f, cf)
+}
+
+func (c *conf) open(file string) (io.ReadCloser, error) {
+
+	if c.fsys != nil {
+		return c.fsys.Open(file)
 	}
+	return os.Open(file)
+}
 
-	return c.read(f, cf)
+func (c *conf) exists(file string) bool {
+
+	var err error
+	if c.fsys != nil {
+		_, err = fs.Stat(c.fsys, file)
+	} else {
+		_, err = os.Stat(file)
+	}
+	return err == nil
 }
 
 func (c *conf) read(f io.Reader, cf interface{}) error {
@@ -54,14 +110,41 @@ func (c *conf) read(f io.Reader, cf interface{}) error {
 		return err
 	}
 
+	if c.dec == nil {
+		c.dec = New()
+	}
+
 	fb := bufio.NewReader(f)
+	c.line = 1
+	c.col = 0
 
-	err := c.readConfig(fb, cf, false)
-	if err != nil {
-		return fmt.Errorf("cannot parse file '%s' line %d: %v", c.file, c.lineNo, err)
+	if c.depth != 0 {
+		return c.readConfig(fb, cf, false)
 	}
 
-	return nil
+	c.state = &readState{
+		defaulted: make(map[string]bool),
+		set:       make(map[string]bool),
+		blockPos:  make(map[string]position),
+		count:     make(map[string]int),
+	}
+
+	err := c.initStruct(reflect.ValueOf(cf).Elem(), "")
+	if err == nil {
+		err = c.readConfig(fb, cf, false)
+	}
+	if err == nil {
+		err = c.finishStruct(reflect.ValueOf(cf).Elem(), "", position{})
+	}
+
+	if len(c.state.errs) == 0 {
+		return err
+	}
+
+	if err != nil {
+		c.state.errs.add(err)
+	}
+	return c.state.errs
 }
 
 func (c *conf) learnConf(cf interface{}) fieldInfo {
@@ -76,62 +159,97 @@ func (c *conf) learnConf(cf interface{}) fieldInfo {
 	typ := val.Type()
 
 	// cache field info
-	if c.info == nil {
-		c.info = make(map[reflect.Type]fieldInfo)
-	}
-	if f, ok := c.info[typ]; ok {
+	c.dec.lock.Lock()
+	defer c.dec.lock.Unlock()
+
+	if f, ok := c.dec.info[typ]; ok {
 		return f
 	}
 
 	sf := reflect.VisibleFields(typ)
 	for i := range sf {
-		name := strings.ToLower(sf[i].Name)
+		name := c.fieldName(sf[i])
 		kind := sf[i].Type.String()
 		tags := sf[i].Tag
 
-		// override default name
-		if n, ok := tags.Lookup("ac/name"); ok {
-			name = n
-		}
-
 		info[name] = sf[i].Index
 		debugf("lrn cf> %s \t%s\t%v\n", name, kind, tags)
 	}
 
-	c.info[typ] = info
+	c.dec.info[typ] = info
 	return info
 }
 
+// fieldName is the name of the field in the config file
+func (c *conf) fieldName(sf reflect.StructField) string {
+
+	// override default name
+	if n, ok := c.tag(sf.Tag, "name"); ok {
+		return n
+	}
+	return strings.ToLower(sf.Name)
+}
+
+// tag looks up a struct tag, using the configured prefix
+func (c *conf) tag(tags reflect.StructTag, name string) (string, bool) {
+	return tags.Lookup(c.dec.tagPrefix + name)
+}
+
 type stringUnmarshaler interface {
 	UnmarshalString(string) error
 }
 
+// TokensUnmarshaler is implemented by types that read all of the values on a line:
+//
+//	listen 0.0.0.0 8080 tls
+//
+// it is used in preference to UnmarshalString and UnmarshalText
+type TokensUnmarshaler interface {
+	UnmarshalACTokens([]string) error
+}
+
 func (c *conf) checkAndStore(cf interface{}, info fieldInfo, k string, v string, extra []string) error {
 
+	kp := []string{k}
+
 	i, ok := info[k]
 	if !ok {
 		// is it a dotted key (param.param)
-		kp := strings.Split(k, ".")
+		kp = strings.Split(k, ".")
 		if len(kp) == 1 {
-			return fmt.Errorf("invalid param '%s'", k)
+			return c.invalidParam(kp, 0, c.block, cf, info)
 		}
 
+		where := c.block
 		for ki, kk := range kp[:len(kp)-1] {
 			i, ok = info[kk]
 			if !ok {
-				return fmt.Errorf("invalid param '%s'", k)
+				return c.invalidParam(kp, ki, where, cf, info)
 			}
 			var cfe = reflect.ValueOf(cf).Elem()
 			var cfv = cfe.FieldByIndex(i)
 
+			if c.dec.pointers && cfv.Kind() == reflect.Pointer && cfv.Type().Elem().Kind() == reflect.Struct {
+				if err := c.allocStruct(cfv, joinPath(where, kk)); err != nil {
+					return err
+				}
+				cfv = cfv.Elem()
+			}
+
 			// dotted - only for structs
 			if cfv.Kind() != reflect.Struct {
 				return fmt.Errorf("invalid type for '%s' %T", k, cfv.Interface())
 			}
 
-			k = kp[ki+1]
 			cf = cfv.Addr().Interface()
 			info = c.learnConf(cf)
+			where = joinPath(where, kk)
+		}
+
+		k = kp[len(kp)-1]
+		i, ok = info[k]
+		if !ok {
+			return c.invalidParam(kp, len(kp)-1, where, cf, info)
 		}
 	}
 
@@ -139,147 +257,124 @@ func (c *conf) checkAndStore(cf interface{}, info fieldInfo, k string, v string,
 	var cfv = cfe.FieldByIndex(i)
 	var tags = cfe.Type().FieldByIndex(i).Tag
 
+	c.markSet(joinPath(c.block, strings.Join(kp, ".")), cfv)
 	return c.checkAndStoreField(cfv, tags, k, v, extra)
 }
 
+// invalidParam reports an unknown param. component bad of the
+// (possibly dotted) param was not found in cf
+func (c *conf) invalidParam(kp []string, bad int, where string, cf interface{}, info fieldInfo) error {
+
+	k := strings.Join(kp, ".")
+
+	if !c.dec.strict {
+		debugf("ignoring param '%s'\n", k)
+		return nil
+	}
+
+	msg := fmt.Sprintf("invalid param '%s' %s", k, describeBlock(where, cf))
+
+	if s := suggest(kp[bad], info); s != "" {
+		fix := append([]string(nil), kp...)
+		fix[bad] = s
+		msg += fmt.Sprintf(", did you mean '%s'?", strings.Join(fix, "."))
+	}
+
+	return errors.New(msg)
+}
 func (c *conf) checkAndStoreField(cfv reflect.Value, tags reflect.StructTag, k string, v string, extra []string) error {
 
+	if err := c.storeField(cfv, tags, k, v, extra); err != nil {
+		return err
+	}
+	return c.checkConstraints(cfv, tags, k, v)
+}
+
+func (c *conf) storeField(cfv reflect.Value, tags reflect.StructTag, k string, v string, extra []string) error {
+
 	iv := cfv
 	if iv.Kind() != reflect.Pointer && iv.Type().Name() != "" && iv.CanAddr() {
 		// convert to pointer type, to find pointer methods
 		iv = iv.Addr()
 	}
 
-	var err error
-	switch tv := iv.Interface().(type) {
-	case stringUnmarshaler:
-		err := tv.UnmarshalString(v)
-		if err != nil {
-			return fmt.Errorf("cannot parse %T for '%s': %v", tv, k, err)
-		}
-		return nil
+	if done, err := c.storeConverted(cfv, tags, k, v); done {
+		return err
+	}
 
-	case encoding.TextUnmarshaler:
-		err := tv.UnmarshalText([]byte(v))
+	if fn, ok := c.dec.types[cfv.Type()]; ok {
+		x, err := fn(v)
 		if err != nil {
-			return fmt.Errorf("cannot parse %T for '%s': %v", tv, k, err)
+			return fmt.Errorf("cannot parse %s for '%s': %w", cfv.Type(), k, err)
 		}
-		return nil
-
-		// time.Time satisfies the TextUnmarshaler interface, but Duration does not
-	case *time.Duration:
-		t, err := time.ParseDuration(v)
-		if err != nil {
-			return fmt.Errorf("cannot parse time.Duration for '%s': %v", k, err)
+		xv := reflect.ValueOf(x)
+		if !xv.IsValid() || !xv.Type().AssignableTo(cfv.Type()) {
+			return fmt.Errorf("cannot use %T as %s for '%s'", x, cfv.Type(), k)
 		}
-		cfv.Set(reflect.ValueOf(t))
+		cfv.Set(xv)
 		return nil
+	}
 
-	case map[string]bool:
-		if tv == nil {
-			tv = make(map[string]bool)
-			cfv.Set(reflect.ValueOf(tv))
-		}
-		if len(extra) > 0 {
-			tv[v] = parseBool(extra[0])
-		} else {
-			tv[v] = true
-		}
-		return nil
+	if bt, ok := builtinTypes[cfv.Type()]; ok {
+		return c.storeBuiltin(cfv, bt, k, v)
+	}
 
-	case map[string]struct{}:
-		if tv == nil {
-			tv = make(map[string]struct{})
-			cfv.Set(reflect.ValueOf(tv))
+	if cfv.Kind() == reflect.Pointer {
+		switch {
+		case c.dec.pointers:
+			// *T - allocated when first set
+			if cfv.IsNil() {
+				cfv.Set(reflect.New(cfv.Type().Elem()))
+			}
+			return c.storeField(cfv.Elem(), tags, k, v, extra)
+		case cfv.IsNil():
+			return fmt.Errorf("field '%s' has unsupported type (%s)", k, cfv.Type())
 		}
-		tv[v] = struct{}{}
+		// otherwise, a *T set before reading may unmarshal itself
+	}
 
-		// permit multiple fields: "param key1 key2 key3"
-		for _, x := range extra {
-			tv[x] = struct{}{}
+	switch tv := iv.Interface().(type) {
+	case TokensUnmarshaler:
+		vals := make([]string, 0, len(extra)+1)
+		vals = append(vals, v)
+		vals = append(vals, extra...)
+
+		err := tv.UnmarshalACTokens(vals)
+		if err != nil {
+			return fmt.Errorf("cannot parse %T for '%s': %w", tv, k, err)
 		}
 		return nil
 
-	case map[string]string:
-		if len(extra) == 0 {
-			return fmt.Errorf("syntax error for %s/%s: value expected", k, v)
-		}
-		if tv == nil {
-			tv = make(map[string]string)
-			cfv.Set(reflect.ValueOf(tv))
+	case stringUnmarshaler:
+		err := tv.UnmarshalString(v)
+		if err != nil {
+			return fmt.Errorf("cannot parse %T for '%s': %w", tv, k, err)
 		}
-		tv[v] = extra[0]
 		return nil
 
-	case map[string]float64:
-		if len(extra) == 0 {
-			return fmt.Errorf("syntax error for %s/%s: value expected", k, v)
-		}
-		if tv == nil {
-			tv = make(map[string]float64)
-			cfv.Set(reflect.ValueOf(tv))
-		}
-		tv[v], err = parseFloat64(extra[0])
-		return err
-	case map[string]float32:
-		if len(extra) == 0 {
-			return fmt.Errorf("syntax error for %s/%s: value expected", k, v)
-		}
-		if tv == nil {
-			tv = make(map[string]float32)
-			cfv.Set(reflect.ValueOf(tv))
-		}
-		tv[v], err = parseFloat32(extra[0])
-		return err
-	case map[string]int64:
-		if len(extra) == 0 {
-			return fmt.Errorf("syntax error for %s/%s: value expected", k, v)
-		}
-		if tv == nil {
-			tv = make(map[string]int64)
-			cfv.Set(reflect.ValueOf(tv))
-		}
-		tv[v], err = parseInt64(extra[0])
-		return err
-	case map[string]int32:
-		if len(extra) == 0 {
-			return fmt.Errorf("syntax error for %s/%s: value expected", k, v)
-		}
-		if tv == nil {
-			tv = make(map[string]int32)
-			cfv.Set(reflect.ValueOf(tv))
-		}
-		tv[v], err = parseInt32(extra[0])
-		return err
-	case map[string]int:
-		if len(extra) == 0 {
-			return fmt.Errorf("syntax error for %s/%s: value expected", k, v)
-		}
-		if tv == nil {
-			tv = make(map[string]int)
-			cfv.Set(reflect.ValueOf(tv))
+	case encoding.TextUnmarshaler:
+		err := tv.UnmarshalText([]byte(v))
+		if err != nil {
+			return fmt.Errorf("cannot parse %T for '%s': %w", tv, k, err)
 		}
-		tv[v], err = parseInt(extra[0])
-		return err
+		return nil
 
-	case map[string]interface{}:
-		if len(extra) == 0 {
-			return fmt.Errorf("syntax error for %s/%s: value expected", k, v)
-		}
-		if tv == nil {
-			tv = make(map[string]interface{})
-			cfv.Set(reflect.ValueOf(tv))
+		// time.Time satisfies the TextUnmarshaler interface, but Duration does not
+	case *time.Duration:
+		if conv, _ := c.tag(tags, "convert"); conv == "duration" {
+			t, err := c.parseDuration(k, v)
+			if err != nil {
+				return err
+			}
+			cfv.Set(reflect.ValueOf(t))
+			return nil
 		}
-		tv[v] = extra[0]
-		return nil
 
-	case []string:
-		tv = append(tv, v)
-		// permit multiple strings: "param value1 value2 value3"
-		if len(extra) > 0 {
-			tv = append(tv, extra...)
+		t, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("cannot parse time.Duration for '%s': %w", k, err)
 		}
-		cfv.Set(reflect.ValueOf(tv))
+		cfv.Set(reflect.ValueOf(t))
 		return nil
 
 	default:
@@ -290,34 +385,71 @@ func (c *conf) checkAndStoreField(cfv reflect.Value, tags reflect.StructTag, k s
 	case reflect.String:
 		cfv.SetString(v)
 
-	case reflect.Int, reflect.Int32, reflect.Int64:
-		conv, _ := tags.Lookup("ac/convert")
-		var ix int64
-		var err error
-
-		switch conv {
-		case "duration":
-			ix, err = parseDuration(v)
-			if err != nil {
-				return fmt.Errorf("invalid value for '%s' (expected duration)\n", k)
-			}
-		default:
-			ix, err = strconv.ParseInt(v, 0, 64)
-			if err != nil {
-				return fmt.Errorf("invalid value for '%s' (expected number)\n", k)
-			}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		ix, err := c.parseInteger(tags, k, v)
+		if err != nil {
+			return err
+		}
+		if cfv.OverflowInt(ix) {
+			return errOutOfRange(cfv, k, v)
 		}
 		cfv.SetInt(ix)
 
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		ux, err := c.parseUnsigned(tags, k, v)
+		if err != nil {
+			return err
+		}
+		if cfv.OverflowUint(ux) {
+			return errOutOfRange(cfv, k, v)
+		}
+		cfv.SetUint(ux)
+
 	case reflect.Float32, reflect.Float64:
-		f, _ := strconv.ParseFloat(v, 64)
-		cfv.SetFloat(f)
+		fx, err := strconv.ParseFloat(v, cfv.Type().Bits())
+		if errors.Is(err, strconv.ErrRange) {
+			return errOutOfRange(cfv, k, v)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid value '%s' for '%s' (expected number)", v, k)
+		}
+		cfv.SetFloat(fx)
 
 	case reflect.Bool:
 		cfv.SetBool(parseBool(v))
 
+	case reflect.Interface:
+		if cfv.NumMethod() != 0 {
+			return fmt.Errorf("field '%s' has unsupported type (%s)", k, cfv.Type())
+		}
+		cfv.Set(reflect.ValueOf(v))
+
+	case reflect.Map:
+		if isSet(cfv.Type()) {
+			// permit multiple keys: "param key1 key2 key3"
+			for _, key := range append([]string{v}, extra...) {
+				if err := c.storeMapEntry(cfv, tags, k, key, nil); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		return c.storeMapEntry(cfv, tags, k, v, extra)
+
 	case reflect.Slice:
 		var typ = cfv.Type().Elem()
+
+		if isList(typ) && !c.isScalar(typ) && !c.hasConverter(tags) || takesTokens(typ) {
+			// list of lists, or of multi-value types: each line is one elem
+			elem := reflect.New(typ)
+			err := c.checkAndStoreField(elem.Elem(), tags, k, v, extra)
+			if err != nil {
+				return err
+			}
+			cfv.Set(reflect.Append(cfv, elem.Elem()))
+			return nil
+		}
+
 		// permit multiple: "param value1 value2 value3"
 		vals := make([]string, 0, len(extra)+1)
 		vals = append(vals, v)
@@ -333,6 +465,22 @@ func (c *conf) checkAndStoreField(cfv reflect.Value, tags reflect.StructTag, k s
 			cfv.Set(reflect.Append(cfv, elem.Elem()))
 		}
 
+	case reflect.Array:
+		// all of the values at once: "param value1 value2 value3"
+		if len(extra)+1 != cfv.Len() {
+			return fmt.Errorf("invalid value for '%s' (expected %d values, found %d)", k, cfv.Len(), len(extra)+1)
+		}
+
+		for i := 0; i < cfv.Len(); i++ {
+			if i > 0 {
+				v = extra[i-1]
+			}
+			err := c.checkAndStoreField(cfv.Index(i), tags, k, v, nil)
+			if err != nil {
+				return err
+			}
+		}
+
 	default:
 		return fmt.Errorf("field '%s' has unsupported type (%s)", k, cfv.Kind().String())
 	}
@@ -352,13 +500,14 @@ func (c *conf) readConfig(f *bufio.Reader, cf interface{}, isBlock bool) error {
 			return nil
 		}
 		if err != nil {
-			return err
+			return c.parseError(position{c.line, c.col}, "", err)
 		}
 		if len(tok) == 0 {
 			continue
 		}
 
 		key := tok[0]
+		pos := c.tokPos[0]
 
 		if isBlock && key == "}" {
 			return nil
@@ -377,28 +526,44 @@ func (c *conf) readConfig(f *bufio.Reader, cf interface{}, isBlock bool) error {
 
 		switch {
 		case val == "{":
-			err = c.readBlock(f, key, cf, cfinfo)
-			if err != nil {
-				return err
-			}
+			err = c.readBlock(f, key, "", pos, cf, cfinfo)
+		case len(tok) == 3 && tok[2] == "{":
+			// labeled block: "key label {"
+			err = c.readBlock(f, key, val, pos, cf, cfinfo)
+		case tok[len(tok)-1] == "{":
+			err = c.blockError(f, pos, key, fmt.Errorf("syntax error: too many labels for section '%s'", key))
 		case key == "include":
-			err = c.include(val, cf)
-			if err != nil {
-				return err
-			}
+			err = c.fail(c.parseError(pos, key, c.include(val, pos, cf)))
 		default:
 			debugf(">>> %s => %s\n", key, val)
+			err = c.fail(c.parseError(pos, key, c.checkAndStore(cf, cfinfo, key, val, extra)))
+		}
 
-			err = c.checkAndStore(cf, cfinfo, key, val, extra)
-			if err != nil {
-				return err
-			}
+		if err != nil {
+			return c.parseError(pos, key, err)
 		}
 	}
 }
 
-func (c *conf) include(file string, cf interface{}) error {
-	return Read(c.includeFile(file), cf)
+func (c *conf) include(file string, pos position, cf interface{}) error {
+
+	if c.depth >= c.dec.maxDepth {
+		return fmt.Errorf("cannot include '%s': too many nested includes (max %d)", file, c.dec.maxDepth)
+	}
+
+	ic := &conf{
+		file:  c.includeFile(file),
+		depth: c.depth + 1,
+		fsys:  c.fsys,
+		dec:   c.dec,
+		state: c.state,
+		block: c.block,
+	}
+
+	ic.included = append(ic.included, Position{c.file, pos.line, pos.col})
+	ic.included = append(ic.included, c.included...)
+
+	return ic.readFile(cf)
 }
 
 func (c *conf) includeFile(file string) string {
@@ -406,7 +571,13 @@ func (c *conf) includeFile(file string) string {
 	if file == "" {
 		return file
 	}
-	if file[0] == '/' {
+
+	if c.fsys != nil {
+		// fs.FS paths are unrooted, and always relative to the top of the fs
+		if file[0] == '/' {
+			return file[1:]
+		}
+	} else if file[0] == '/' {
 		return file
 	}
 
@@ -416,28 +587,51 @@ func (c *conf) includeFile(file string) string {
 	dir := path.Dir(c.file)
 	debugf("inc dir %s, file %s\n", dir, file)
 
-	if dir == "" {
-		return file
+	rel := file
+	if c.fsys != nil {
+		rel = path.Join(dir, file)
+	} else if dir != "" {
+		rel = dir + "/" + file
 	}
 
-	return dir + "/" + file
+	if len(c.dec.incPath) == 0 || c.exists(rel) {
+		return rel
+	}
+
+	// otherwise, check the search path
+	for _, dir := range c.dec.incPath {
+		p := path.Join(dir, file)
+		if c.fsys != nil {
+			p = strings.TrimPrefix(p, "/")
+		}
+		if c.exists(p) {
+			return p
+		}
+	}
 
+	return rel
 }
 
 func (c *conf) readLine(f *bufio.Reader) ([]string, error) {
 
 	var res []string
+	c.tokPos = c.tokPos[:0]
 
 	for {
 		s, delim, err := c.readToken(f, len(res) == 0)
 		debugf(">> tok %v, %v, %v\n", s, delim, err)
 
+		if err == io.EOF && len(res) != 0 {
+			// last line, without a newline. EOF is returned next time
+			return res, nil
+		}
 		if err != nil {
 			return nil, err
 		}
 
 		if s != "" {
 			res = append(res, s)
+			c.tokPos = append(c.tokPos, c.start)
 		}
 
 		if delim == '\n' {
@@ -446,11 +640,33 @@ func (c *conf) readLine(f *bufio.Reader) ([]string, error) {
 	}
 }
 
+// readByte reads the next byte, keeping track of the position
+func (c *conf) readByte(f *bufio.Reader) (byte, error) {
+
+	ch, err := f.ReadByte()
+	if err != nil {
+		return ch, err
+	}
+
+	if ch == '\n' {
+		c.line++
+		c.col = 0
+	} else {
+		c.col++
+	}
+	return ch, nil
+}
+
 func (c *conf) readToken(f *bufio.Reader, orcolon bool) (string, int, error) {
 	var buf []byte
+	var started bool
 
 	for {
-		ch, err := f.ReadByte()
+		ch, err := c.readByte(f)
+		if err == io.EOF && started {
+			// last token, without a newline. EOF is returned next time
+			return string(buf), '\n', nil
+		}
 		if err != nil {
 			return "", -1, err
 		}
@@ -458,7 +674,10 @@ func (c *conf) readToken(f *bufio.Reader, orcolon bool) (string, int, error) {
 		switch ch {
 		case '#':
 			// comment until eol
-			err = eatLine(f)
+			err = c.eatLine(f)
+			if err == io.EOF {
+				return string(buf), '\n', nil
+			}
 			if err != nil {
 				return "", -1, err
 			}
@@ -469,6 +688,10 @@ func (c *conf) readToken(f *bufio.Reader, orcolon bool) (string, int, error) {
 			return string(buf), '\n', nil
 
 		case '"', '\'':
+			if !started {
+				c.start = position{c.line, c.col}
+				started = true
+			}
 			// read until matching quote
 			b, err := c.readQuoted(f, ch)
 			if err != nil {
@@ -477,6 +700,21 @@ func (c *conf) readToken(f *bufio.Reader, orcolon bool) (string, int, error) {
 			buf = append(buf, b...)
 			continue
 
+		case '$':
+			if !c.isVarRef(f) {
+				break
+			}
+			if !started {
+				c.start = position{c.line, c.col}
+				started = true
+			}
+			b, err := c.readVar(f)
+			if err != nil {
+				return "", '\n', err
+			}
+			buf = append(buf, b...)
+			continue
+
 		case ':':
 			// permit colon to delimit first token
 			if !orcolon {
@@ -491,24 +729,43 @@ func (c *conf) readToken(f *bufio.Reader, orcolon bool) (string, int, error) {
 			continue
 		}
 
+		if !started {
+			c.start = position{c.line, c.col}
+			started = true
+		}
 		buf = append(buf, ch)
 	}
 }
 
 func (c *conf) readQuoted(f *bufio.Reader, delim byte) ([]byte, error) {
 	var buf []byte
+	var pos = position{c.line, c.col}
 
 	for {
-		ch, err := f.ReadByte()
+		ch, err := c.readByte(f)
+		if err == io.EOF {
+			return nil, c.parseError(pos, "", fmt.Errorf("unterminated quoted string"))
+		}
 		if err != nil {
 			return nil, err
 		}
 		if ch == delim {
 			break
 		}
+		if ch == '$' && delim == '"' && c.isVarRef(f) {
+			b, err := c.readVar(f)
+			if err != nil {
+				return nil, err
+			}
+			buf = append(buf, b...)
+			continue
+		}
 		if ch == '\\' {
 			// \" \' to include a quote
-			ch, err = f.ReadByte()
+			ch, err = c.readByte(f)
+			if err == io.EOF {
+				return nil, c.parseError(pos, "", fmt.Errorf("unterminated quoted string"))
+			}
 			if err != nil {
 				return nil, err
 			}
@@ -531,7 +788,21 @@ func (c *conf) readQuoted(f *bufio.Reader, delim byte) ([]byte, error) {
 	return buf, nil
 }
 
-func (c *conf) readMap(f *bufio.Reader, cf interface{}) error {
+// eatLine discards the rest of the line
+func (c *conf) eatLine(f *bufio.Reader) error {
+
+	for {
+		ch, err := c.readByte(f)
+		if err != nil {
+			return err
+		}
+		if ch == '\n' {
+			return nil
+		}
+	}
+}
+
+func (c *conf) readMap(f *bufio.Reader, m reflect.Value, tags reflect.StructTag, name string) error {
 
 	for {
 		tok, err := c.readLine(f)
@@ -541,13 +812,14 @@ func (c *conf) readMap(f *bufio.Reader, cf interface{}) error {
 			return nil
 		}
 		if err != nil {
-			return err
+			return c.parseError(position{c.line, c.col}, "", err)
 		}
 		if len(tok) == 0 {
 			continue
 		}
 
 		key := tok[0]
+		pos := c.tokPos[0]
 
 		if key == "}" {
 			return nil
@@ -559,49 +831,144 @@ func (c *conf) readMap(f *bufio.Reader, cf interface{}) error {
 		}
 		debugf(">>> %s => %s\n", key, val)
 
-		switch m := cf.(type) {
-		case map[string]string:
-			m[key] = val
-		case map[string]float64:
-			m[key], err = parseFloat64(val)
-		case map[string]float32:
-			m[key], err = parseFloat32(val)
-		case map[string]int64:
-			m[key], err = parseInt64(val)
-		case map[string]int32:
-			m[key], err = parseInt32(val)
-		case map[string]int:
-			m[key], err = parseInt(val)
-		case map[string]interface{}:
-			m[key] = val
-		case map[string]bool:
-			if len(tok) > 2 {
-				m[key] = parseBool(tok[2])
-			} else {
-				m[key] = true
+		vals := tok[1:]
+		switch m.Type().Elem().Kind() {
+		case reflect.String, reflect.Interface:
+			// value is optional
+			if len(vals) == 0 {
+				vals = []string{""}
 			}
-		case map[string]struct{}:
-			m[key] = struct{}{}
-		default:
-			return fmt.Errorf("invalid map type %T, try map[string]string", cf)
 		}
+
+		err = c.storeMapEntry(m, tags, name, key, vals)
+		if err = c.fail(c.parseError(pos, key, err)); err != nil {
+			return err
+		}
+	}
+}
+
+// readList reads the values in a block into a slice
+func (c *conf) readList(f *bufio.Reader, fv reflect.Value, tags reflect.StructTag, name string) error {
+
+	for {
+		tok, err := c.readLine(f)
+
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return c.parseError(position{c.line, c.col}, "", err)
+		}
+		if len(tok) == 0 {
+			continue
+		}
+
+		pos := c.tokPos[0]
+
+		if tok[0] == "}" {
+			return nil
+		}
+		if tok[len(tok)-1] == "{" {
+			if err = c.blockError(f, pos, name, fmt.Errorf("syntax error: unexpected block in '%s'", name)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		err = c.checkAndStoreField(fv, tags, name, tok[0], tok[1:])
+		if err = c.fail(c.parseError(pos, name, err)); err != nil {
+			return err
+		}
+	}
+}
+
+// storeMapEntry parses the key and value, and adds them to the map
+func (c *conf) storeMapEntry(m reflect.Value, tags reflect.StructTag, k string, key string, vals []string) error {
+
+	var typ = m.Type()
+
+	mk := reflect.New(typ.Key()).Elem()
+	if err := c.storeField(mk, "", k+" key", key, nil); err != nil {
+		return err
+	}
+
+	elem := reflect.New(typ.Elem()).Elem()
+
+	switch {
+	case isSet(typ):
+		// no value
+	case typ.Elem().Kind() == reflect.Bool && len(vals) == 0:
+		// "param key" => true
+		elem.SetBool(true)
+	case len(vals) == 0:
+		return fmt.Errorf("syntax error for %s/%s: value expected", k, key)
+	default:
+		err := c.checkAndStoreField(elem, tags, fmt.Sprintf("%s[%s]", k, key), vals[0], vals[1:])
 		if err != nil {
 			return err
 		}
 	}
+
+	if m.IsNil() {
+		m.Set(reflect.MakeMap(typ))
+	}
+
+	m.SetMapIndex(mk, elem)
+	return nil
+}
+
+// isList checks for a slice or array
+func isList(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array
 }
 
-func (c *conf) readBlock(f *bufio.Reader, sect string, cf interface{}, info fieldInfo) error {
+// isSet checks for a map[K]struct{}
+func isSet(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Map && typ.Elem().Kind() == reflect.Struct && typ.Elem().NumField() == 0
+}
+
+func (c *conf) readBlock(f *bufio.Reader, sect string, label string, pos position, cf interface{}, info fieldInfo) error {
 
 	i, ok := info[sect]
 	if !ok {
-		return fmt.Errorf("invalid section '%s'", sect)
+		if !c.dec.strict {
+			debugf("ignoring section '%s'\n", sect)
+			return c.skipBlock(f)
+		}
+		msg := fmt.Sprintf("invalid section '%s' %s", sect, describeBlock(c.block, cf))
+		if s := suggest(sect, info); s != "" {
+			msg += fmt.Sprintf(", did you mean '%s'?", s)
+		}
+		return c.blockError(f, pos, sect, errors.New(msg))
 	}
 
 	var cfe = reflect.ValueOf(cf).Elem()
 	var cft = cfe.Type().FieldByIndex(i).Type
 
+	if isBlockUnmarshaler(cfe.FieldByIndex(i)) {
+		return c.readCustomBlock(f, sect, label, pos, cfe.FieldByIndex(i))
+	}
+
+	if c.isStructMap(cft) {
+		return c.readStructMap(f, sect, label, pos, cfe.FieldByIndex(i))
+	}
+
+	if cft.Kind() == reflect.Slice && !c.isStructList(cft) {
+		// slice of values, one or more per line
+		if label != "" {
+			return c.blockError(f, pos, sect, fmt.Errorf("section '%s' does not take a label", sect))
+		}
+		c.markSet(joinPath(c.block, sect), cfe.FieldByIndex(i))
+		return c.readList(f, cfe.FieldByIndex(i), cfe.Type().FieldByIndex(i).Tag, sect)
+	}
+
+	if label != "" && cft.Kind() != reflect.Slice {
+		return c.blockError(f, pos, sect, fmt.Errorf("section '%s' does not take a label", sect))
+	}
+
 	if cft.Kind() == reflect.Map {
+		c.markSet(joinPath(c.block, sect), cfe.FieldByIndex(i))
+		defer c.enterBlock(sect)()
 		newcf := cfe.FieldByIndex(i)
 
 		if newcf.IsNil() {
@@ -610,95 +977,293 @@ func (c *conf) readBlock(f *bufio.Reader, sect string, cf interface{}, info fiel
 			cfe.FieldByIndex(i).Set(newcf)
 		}
 
-		return c.readMap(f, newcf.Interface())
+		return c.readMap(f, newcf, cfe.Type().FieldByIndex(i).Tag, sect)
 	}
 
-	// *struct - disabled to simplify user code (no nil)
-	if false && cft.Kind() == reflect.Pointer {
+	// *struct - only if enabled, to simplify user code (no nil)
+	if c.dec.pointers && cft.Kind() == reflect.Pointer && cft.Elem().Kind() == reflect.Struct {
 		s := cfe.FieldByIndex(i)
-		if s.IsNil() {
-			// create struct
-			s = reflect.New(cft.Elem())
-			cfe.FieldByIndex(i).Set(s)
+		c.markSet(joinPath(c.block, sect), s)
+		defer c.enterBlock(sect)()
+		if _, ok := c.state.blockPos[c.block]; !ok {
+			c.state.blockPos[c.block] = pos
+		}
+		if err := c.allocStruct(s, c.block); err != nil {
+			return c.blockError(f, pos, sect, err)
 		}
 		return c.readConfig(f, s.Interface(), true)
 	}
 
 	if cft.Kind() == reflect.Struct {
 		// nested struct
+		c.markSet(joinPath(c.block, sect), cfe.FieldByIndex(i))
+		defer c.enterBlock(sect)()
+		if _, ok := c.state.blockPos[c.block]; !ok {
+			c.state.blockPos[c.block] = pos
+		}
 		var cfv = cfe.FieldByIndex(i).Addr().Interface()
 		return c.readConfig(f, cfv, true)
 	}
 
-	// validate type is slice of pointer to struct
-	if cft.Kind() != reflect.Slice || cft.Elem().Kind() != reflect.Ptr || cft.Elem().Elem().Kind() != reflect.Struct {
-		return fmt.Errorf("invalid config type '%T'. should be []*struct, struct, or map", cf)
+	// validate type is slice or array of struct or pointer to struct
+	if !c.isStructList(cft) {
+		return c.blockError(f, pos, sect, fmt.Errorf("invalid config type '%s'. should be []*struct, []struct, struct, or map", cft))
 	}
 
+	var cfv = cfe.FieldByIndex(i)
+	var fpath = joinPath(c.block, sect)
+	var n int
+
+	c.markSet(fpath, cfv)
+
 	// create new one
-	var typ = cft.Elem().Elem()
-	newcf := reflect.New(typ).Interface()
+	if cft.Kind() == reflect.Array {
+		n = c.state.count[fpath]
+		if n >= cfv.Len() {
+			return c.blockError(f, pos, sect, fmt.Errorf("too many '%s' sections (max %d)", sect, cfv.Len()))
+		}
+		c.state.count[fpath] = n + 1
+	} else {
+		n = cfv.Len()
+		cfv.Set(reflect.Append(cfv, reflect.Zero(cft.Elem())))
+	}
+
+	newcf := cfv.Index(n)
+	if newcf.Kind() == reflect.Pointer {
+		newcf.Set(reflect.New(newcf.Type().Elem()))
+		newcf = newcf.Elem()
+	}
+
+	defer c.enterBlock(fmt.Sprintf("%s[%d]", sect, n))()
 
 	// init newcf
-	// ...
+	if err := c.initStruct(newcf, c.block); err != nil {
+		return c.blockError(f, pos, sect, err)
+	}
 
-	var cfv = cfe.FieldByIndex(i)
-	cfv.Set(reflect.Append(cfv, reflect.ValueOf(newcf)))
+	if label != "" {
+		if err := c.setLabel(newcf, sect, label, true); err != nil {
+			return c.blockError(f, pos, sect, err)
+		}
+	}
 
-	err := c.readConfig(f, newcf, true)
+	err := c.readConfig(f, newcf.Addr().Interface(), true)
+	if err != nil {
+		return err
+	}
 
-	return err
+	return c.finishStruct(newcf, c.block, pos)
 }
 
-func isPtrStruct(cf interface{}) error {
-	var typ = reflect.TypeOf(cf)
+// enterBlock notes that we are inside the named block. call the returned func when leaving
+func (c *conf) enterBlock(name string) func() {
 
-	if typ.Kind() != reflect.Pointer || typ.Elem().Kind() != reflect.Struct {
-		return fmt.Errorf("invalid result type, should be *struct")
+	saved := c.block
+	c.block = joinPath(saved, name)
+	return func() {
+		c.block = saved
 	}
-	return nil
 }
 
-func eatLine(f *bufio.Reader) error {
-	_, _, err := f.ReadLine()
-	return err
+func joinPath(path, name string) string {
+
+	if path == "" {
+		return name
+	}
+	return path + "." + name
 }
 
-func debugf(txt string, args ...interface{}) {
-	if dEBUG {
-		fmt.Printf(txt, args...)
+// blockError reports an error with a block, and skips over it if we are continuing
+func (c *conf) blockError(f *bufio.Reader, pos position, sect string, err error) error {
+
+	if err = c.fail(c.parseError(pos, sect, err)); err != nil {
+		return err
 	}
+	return c.skipBlock(f)
 }
 
-// time.Duration is great for short durations (microsecs)
-// but useless for real-world durations
-// NB: days, months, and years are based on "typical" values and not exact
-// returns seconds
-func parseDuration(v string) (int64, error) {
+// skipBlock discards the rest of a block, including any nested blocks
+func (c *conf) skipBlock(f *bufio.Reader) error {
 
-	var lc = v[len(v)-1]
-	var i int64
-	var err error
+	depth := 1
+	for depth > 0 {
+		tok, err := c.readLine(f)
+		if err != nil {
+			return err
+		}
+		if len(tok) == 0 {
+			continue
+		}
+
+		switch {
+		case tok[0] == "}":
+			depth--
+		case tok[len(tok)-1] == "{":
+			depth++
+		}
+	}
+	return nil
+}
+
+// readStructMap reads a labeled block into a map of structs: "key label { ... }"
+func (c *conf) readStructMap(f *bufio.Reader, sect string, label string, pos position, m reflect.Value) error {
+
+	if label == "" {
+		return c.blockError(f, pos, sect, fmt.Errorf("section '%s' requires a label", sect))
+	}
+
+	var typ = m.Type()
+	var isPtr = typ.Elem().Kind() == reflect.Pointer
+
+	mk := reflect.New(typ.Key()).Elem()
+	if err := c.storeField(mk, "", sect+" label", label, nil); err != nil {
+		return c.blockError(f, pos, sect, err)
+	}
+
+	c.markSet(joinPath(c.block, sect), m)
+	defer c.enterBlock(fmt.Sprintf("%s[%s]", sect, label))()
+
+	if m.IsNil() {
+		m.Set(reflect.MakeMap(typ))
+	}
+
+	// work on a pointer to the struct
+	var newcf reflect.Value
+
+	if old := m.MapIndex(mk); old.IsValid() {
+		if !c.dec.merge {
+			return c.blockError(f, pos, sect, fmt.Errorf("duplicate section '%s %s'", sect, label))
+		}
+		if isPtr {
+			newcf = old
+		} else {
+			// map values are not addressable, work on a copy
+			newcf = reflect.New(typ.Elem())
+			newcf.Elem().Set(old)
+		}
+	} else {
+		if isPtr {
+			newcf = reflect.New(typ.Elem().Elem())
+		} else {
+			newcf = reflect.New(typ.Elem())
+		}
+		if err := c.initStruct(newcf.Elem(), c.block); err != nil {
+			return c.blockError(f, pos, sect, err)
+		}
+		if err := c.setLabel(newcf.Elem(), sect, label, false); err != nil {
+			return c.blockError(f, pos, sect, err)
+		}
+	}
 
-	if lc >= '0' && lc <= '9' {
-		i, err = strconv.ParseInt(v, 0, 32)
+	err := c.readConfig(f, newcf.Interface(), true)
+
+	if isPtr {
+		m.SetMapIndex(mk, newcf)
 	} else {
-		i, err = strconv.ParseInt(v[0:len(v)-1], 0, 32)
+		m.SetMapIndex(mk, newcf.Elem())
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return c.finishStruct(newcf.Elem(), c.block, pos)
+}
+
+// setLabel stores a block label into the field tagged `ac/label`
+func (c *conf) setLabel(v reflect.Value, sect string, label string, required bool) error {
 
-		switch unicode.ToLower(rune(lc)) {
-		case 'y':
-			i *= 3600 * 24 * 365
-		case 'm':
-			i *= 3600 * 24 * 28
-		case 'd':
-			i *= 3600 * 24
-		case 'h':
-			i *= 3600
+	for _, sf := range reflect.VisibleFields(v.Type()) {
+		if _, ok := c.tag(sf.Tag, "label"); !ok || !sf.IsExported() {
+			continue
+		}
+
+		fv, err := v.FieldByIndexErr(sf.Index)
+		if err != nil {
+			continue
 		}
 
+		name := c.fieldName(sf)
+		c.markSet(joinPath(c.block, name), fv)
+		return c.checkAndStoreField(fv, sf.Tag, name, label, nil)
+	}
+
+	if required {
+		return fmt.Errorf("section '%s' does not take a label (no %slabel field)", sect, c.dec.tagPrefix)
+	}
+	return nil
+}
+
+// isStructList checks for a slice or array of struct or *struct
+func (c *conf) isStructList(typ reflect.Type) bool {
+
+	if typ.Kind() != reflect.Slice && typ.Kind() != reflect.Array {
+		return false
+	}
+
+	elem := typ.Elem()
+	if elem.Kind() == reflect.Pointer {
+		elem = elem.Elem()
+	}
+	return elem.Kind() == reflect.Struct && !c.isScalar(elem)
+}
+
+// isStructMap checks for a map[K]struct or map[K]*struct
+func (c *conf) isStructMap(typ reflect.Type) bool {
+
+	if typ.Kind() != reflect.Map || isSet(typ) {
+		return false
+	}
+
+	elem := typ.Elem()
+	if elem.Kind() == reflect.Pointer {
+		elem = elem.Elem()
+	}
+	return elem.Kind() == reflect.Struct && !c.isScalar(elem)
+}
+
+var (
+	textUnmarshalerType   = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	stringUnmarshalerType = reflect.TypeOf((*stringUnmarshaler)(nil)).Elem()
+	tokensUnmarshalerType = reflect.TypeOf((*TokensUnmarshaler)(nil)).Elem()
+)
+
+// isScalar checks if the type is parsed from a single value (eg. time.Time)
+func (c *conf) isScalar(typ reflect.Type) bool {
+
+	if _, ok := c.dec.types[typ]; ok {
+		return true
+	}
+	if _, ok := builtinTypes[typ]; ok {
+		return true
+	}
+
+	ptr := reflect.PointerTo(typ)
+	return ptr.Implements(textUnmarshalerType) || ptr.Implements(stringUnmarshalerType) || ptr.Implements(tokensUnmarshalerType)
+}
+
+// takesTokens checks if the type reads all of the values on a line
+func takesTokens(typ reflect.Type) bool {
+
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	return reflect.PointerTo(typ).Implements(tokensUnmarshalerType)
+}
+
+func isPtrStruct(cf interface{}) error {
+	var typ = reflect.TypeOf(cf)
+
+	if typ.Kind() != reflect.Pointer || typ.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("invalid result type, should be *struct")
 	}
+	return nil
+}
 
-	return i, err
+func debugf(txt string, args ...interface{}) {
+	if dEBUG {
+		fmt.Printf(txt, args...)
+	}
 }
 
 func parseBool(v string) bool {
@@ -710,23 +1275,71 @@ func parseBool(v string) bool {
 	return false
 }
 
-func parseFloat64(s string) (float64, error) {
-	v, err := strconv.ParseFloat(s, 64)
-	return v, err
-}
-func parseFloat32(s string) (float32, error) {
-	v, err := strconv.ParseFloat(s, 32)
-	return float32(v), err
-}
-func parseInt64(s string) (int64, error) {
-	v, err := strconv.ParseInt(s, 0, 64)
-	return v, err
+// parseInteger parses a signed integer param, applying any conversion
+func (c *conf) parseInteger(tags reflect.StructTag, k string, v string) (int64, error) {
+
+	conv, _ := c.tag(tags, "convert")
+
+	switch conv {
+	case "duration":
+		return c.parseSeconds(k, v)
+	case "size":
+		ux, err := c.parseUnsigned(tags, k, v)
+		if err != nil {
+			return 0, err
+		}
+		if ux > math.MaxInt64 {
+			return 0, fmt.Errorf("invalid value '%s' for '%s' (out of range)", v, k)
+		}
+		return int64(ux), nil
+	}
+
+	ix, err := strconv.ParseInt(v, 0, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		return 0, fmt.Errorf("invalid value '%s' for '%s' (out of range)", v, k)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("invalid value '%s' for '%s' (expected number)", v, k)
+	}
+	return ix, nil
 }
-func parseInt32(s string) (int32, error) {
-	v, err := strconv.ParseInt(s, 0, 64)
-	return int32(v), err
+
+// parseUnsigned parses an unsigned integer param, applying any conversion
+func (c *conf) parseUnsigned(tags reflect.StructTag, k string, v string) (uint64, error) {
+
+	conv, _ := c.tag(tags, "convert")
+
+	switch conv {
+	case "duration":
+		ix, err := c.parseInteger(tags, k, v)
+		if err != nil {
+			return 0, err
+		}
+		if ix < 0 {
+			return 0, fmt.Errorf("invalid value '%s' for '%s' (must not be negative)", v, k)
+		}
+		return uint64(ix), nil
+	case "size":
+		ux, err := parseSize(v)
+		if err == strconv.ErrRange {
+			return 0, fmt.Errorf("invalid value '%s' for '%s' (out of range)", v, k)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("invalid value '%s' for '%s' (expected size)", v, k)
+		}
+		return ux, nil
+	}
+
+	ux, err := strconv.ParseUint(v, 0, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		return 0, fmt.Errorf("invalid value '%s' for '%s' (out of range)", v, k)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("invalid value '%s' for '%s' (expected unsigned number)", v, k)
+	}
+	return ux, nil
 }
-func parseInt(s string) (int, error) {
-	v, err := strconv.ParseInt(s, 0, 64)
-	return int(v), err
+
+func errOutOfRange(cfv reflect.Value, k string, v string) error {
+	return fmt.Errorf("invalid value '%s' for '%s' (out of range for %s)", v, k, cfv.Type())
 }
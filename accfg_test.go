@@ -8,8 +8,13 @@ package acconfig
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
+	"net"
+	"net/netip"
+	"strconv"
 	"testing"
+	"testing/fstest"
 	"time"
 )
 
@@ -35,7 +40,9 @@ func TestReadLine(t *testing.T) {
 		{false, "\n", nil},
 		{false, " # comment\n", nil},
 		{true, "field \"value\n", nil},
-		{true, "field value", nil},
+		{false, "field value", []string{"field", "value"}},
+		{false, "field 'value'", []string{"field", "value"}},
+		{false, "field value # comment", []string{"field", "value"}},
 		{true, "", nil},
 	}
 
@@ -324,6 +331,35 @@ param lorem-ipsum
 	}
 }
 
+func TestPresetPointer(t *testing.T) {
+
+	type stuff struct {
+		Color *color
+		Addr  *netip.Addr
+		Other *color
+	}
+
+	// without WithPointers, a *T set before reading still unmarshals into it
+	var c color
+	var a netip.Addr
+	data := stuff{Color: &c, Addr: &a}
+
+	err := ReadString("test", "color red\naddr ::1\n", &data)
+
+	if err != nil {
+		t.Fatalf("failed: %v", err)
+	}
+	if c != "red" || a != netip.IPv6Loopback() {
+		t.Errorf("failed: %v %v", c, a)
+	}
+
+	// but nil is not allocated
+	err = ReadString("test", "other red\n", &data)
+	if err == nil || data.Other != nil {
+		t.Errorf("expected to fail: %+v", data)
+	}
+}
+
 func TestFail4(t *testing.T) {
 
 	type stuff struct {
@@ -427,19 +463,86 @@ param 234 567
 		t.Errorf("failed: expected len 3, got %+v", data)
 	}
 
-	// should fail
+	// block syntax
+	data = stuff{}
 	txt = bytes.NewBufferString(`
 param {
     123
-    234
+    234 567
+
+    890
 }
 `)
 	err = conf.read(txt, &data)
 
-	if err == nil {
-		t.Errorf("expected an error")
+	if err != nil {
+		t.Errorf("failed: %v", err)
+	}
+	if len(data.Param) != 4 || data.Param[3] != 890 {
+		t.Errorf("failed: expected len 4, got %+v", data)
+	}
+
+	// should fail
+	fails := []string{
+		"param {\n    123\n    big\n}\n",
+		"param 123 {\n    234\n}\n",
+		"param {\n    nested {\n    }\n}\n",
 	}
 
+	for _, s := range fails {
+		data = stuff{}
+		err = conf.read(bytes.NewBufferString(s), &data)
+		if err == nil {
+			t.Errorf("expected to fail: '%s'", s)
+		}
+	}
+}
+
+func TestListBlock(t *testing.T) {
+
+	type stuff struct {
+		Allow []net.IP
+		Route [][]string
+		Tag   []string `ac/default:"lorem" ac/len:"..9"`
+	}
+
+	var data stuff
+
+	err := ReadString("test", `
+allow {
+    10.1.2.3
+    10.1.2.4  10.1.2.5
+}
+route {
+    a b c
+    d
+}
+tag {
+    momerath
+}
+`, &data)
+
+	if err != nil {
+		t.Fatalf("failed: %v", err)
+	}
+	if len(data.Allow) != 3 || data.Allow[2].String() != "10.1.2.5" {
+		t.Errorf("failed: %+v", data.Allow)
+	}
+	if len(data.Route) != 2 || len(data.Route[0]) != 3 || data.Route[1][0] != "d" {
+		t.Errorf("failed: %+v", data.Route)
+	}
+	if len(data.Tag) != 1 || data.Tag[0] != "momerath" {
+		t.Errorf("failed: %+v", data.Tag)
+	}
+
+	// constraints apply to each element
+	data = stuff{}
+	err = ReadString("test", "tag {\n    momerath\n    jubjubtree\n}\n", &data)
+
+	pe, ok := err.(*ParseError)
+	if !ok || pe.Line != 3 || pe.Key != "tag" {
+		t.Errorf("expected to fail on line 3, got %T: %v", err, err)
+	}
 }
 
 func TestNested(t *testing.T) {
@@ -520,3 +623,698 @@ func TestDotted2(t *testing.T) {
 	}
 
 }
+
+func TestReadFS(t *testing.T) {
+
+	type stuff struct {
+		Name string
+		Size int
+		Tag  []string
+	}
+
+	fsys := fstest.MapFS{
+		"main.conf":      {Data: []byte("name gizmo\ninclude sub/more.conf\n")},
+		"sub/more.conf":  {Data: []byte("size 123\ninclude other.conf\n")},
+		"sub/other.conf": {Data: []byte("tag borogrove\ninclude /top.conf\n")},
+		"top.conf":       {Data: []byte("tag slithytove\n")},
+	}
+
+	var data stuff
+
+	err := ReadFS(fsys, "main.conf", &data)
+
+	if err != nil {
+		t.Fatalf("failed: %v", err)
+	}
+	if data.Name != "gizmo" || data.Size != 123 {
+		t.Errorf("failed: got %+v", data)
+	}
+	if len(data.Tag) != 2 || data.Tag[1] != "slithytove" {
+		t.Errorf("failed to read include: got %+v", data)
+	}
+
+	err = ReadFS(fsys, "missing.conf", &data)
+	if err == nil {
+		t.Errorf("expected to fail")
+	}
+}
+
+func TestReadString(t *testing.T) {
+
+	type stuff struct {
+		Name string
+		Size int
+	}
+
+	var data stuff
+
+	err := ReadString("test", "name gizmo\nsize 123\n", &data)
+
+	if err != nil {
+		t.Fatalf("failed: %v", err)
+	}
+	if data.Name != "gizmo" || data.Size != 123 {
+		t.Errorf("failed: got %+v", data)
+	}
+
+	// no newline at the end
+	data = stuff{}
+	err = ReadString("test", "name gizmo\nsize 123", &data)
+
+	if err != nil {
+		t.Fatalf("failed: %v", err)
+	}
+	if data.Name != "gizmo" || data.Size != 123 {
+		t.Errorf("failed: got %+v", data)
+	}
+
+	data = stuff{}
+	err = ReadString("test", "name 'gizmo' # comment", &data)
+	if err != nil || data.Name != "gizmo" {
+		t.Errorf("failed: got %+v %v", data, err)
+	}
+
+	err = ReadString("test", "name gizmo\nsize big\n", &data)
+	if err == nil {
+		t.Errorf("expected to fail")
+	}
+
+	err = ReadString("test", "name gizmo\nsize big", &data)
+	if pe, ok := err.(*ParseError); !ok || pe.Line != 2 || pe.Key != "size" {
+		t.Errorf("expected to fail on line 2, got %v", err)
+	}
+}
+
+func TestIntegers(t *testing.T) {
+
+	type stuff struct {
+		I8    int8
+		I16   int16
+		I32   int32
+		I64   int64
+		U     uint
+		U8    uint8
+		Port  uint16
+		Perm  uint32
+		U64   uint64
+		Ptr   uintptr
+		Secs  uint32 `ac/convert:"duration"`
+		Ports []uint16
+	}
+
+	var data stuff
+
+	err := ReadString("test", `
+i8    -128
+i16   32767
+i32   -2147483648
+i64   9223372036854775807
+u     0x10
+u8    255
+port  8080
+perm  0o644
+u64   18446744073709551615
+ptr   1234
+secs  1h
+ports 80 443
+`, &data)
+
+	if err != nil {
+		t.Fatalf("failed: %v", err)
+	}
+
+	if data.I8 != -128 || data.I16 != 32767 || data.I32 != -2147483648 || data.I64 != 9223372036854775807 {
+		t.Errorf("failed to read signed: %+v", data)
+	}
+	if data.U != 16 || data.U8 != 255 || data.Port != 8080 || data.Perm != 0644 || data.U64 != 18446744073709551615 || data.Ptr != 1234 {
+		t.Errorf("failed to read unsigned: %+v", data)
+	}
+	if data.Secs != 3600 || len(data.Ports) != 2 || data.Ports[1] != 443 {
+		t.Errorf("failed to read: %+v", data)
+	}
+
+	fails := []string{
+		"i8 128\n",
+		"i8 -129\n",
+		"i16 32768\n",
+		"i32 2147483648\n",
+		"i64 9223372036854775808\n",
+		"u8 256\n",
+		"u8 -1\n",
+		"port 65536\n",
+		"perm 4294967296\n",
+		"u64 18446744073709551616\n",
+		"ports 80 65536\n",
+		"port http\n",
+	}
+
+	for _, txt := range fails {
+		err := ReadString("test", txt, &data)
+		if err == nil {
+			t.Errorf("expected to fail: '%s'", txt)
+		}
+	}
+
+	err = ReadString("test", "port 65536\n", &data)
+	if err == nil || err.Error() != "cannot parse file 'test' line 1 col 1: invalid value '65536' for 'port' (out of range for uint16)" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestNumberErrors(t *testing.T) {
+
+	type stuff struct {
+		Rate   float64
+		Small  float32
+		Rates  []float32
+		Limit  map[string]int32
+		Cost   map[string]float32
+		Counts map[string]int
+	}
+
+	var data stuff
+
+	err := ReadString("test", `
+rate   1.5e3
+small  3.4e38
+rates  1 2.5 -3
+limit  api 2147483647
+cost {
+    sugar 1.23
+}
+counts eggs 12
+`, &data)
+
+	if err != nil {
+		t.Fatalf("failed: %v", err)
+	}
+	if data.Rate != 1500 || data.Small != 3.4e38 || len(data.Rates) != 3 || data.Rates[1] != 2.5 {
+		t.Errorf("failed: %+v", data)
+	}
+	if data.Limit["api"] != 2147483647 || data.Cost["sugar"] != 1.23 || data.Counts["eggs"] != 12 {
+		t.Errorf("failed: %+v", data)
+	}
+
+	type testdata struct {
+		txt    string
+		expect string
+	}
+
+	tests := []testdata{
+		{"rate 1.5x\n", "invalid value '1.5x' for 'rate' (expected number)"},
+		{"small 3.5e38\n", "invalid value '3.5e38' for 'small' (out of range for float32)"},
+		{"rates 1 2,5\n", "invalid value '2,5' for 'rates' (expected number)"},
+		{"limit api 2147483648\n", "invalid value '2147483648' for 'limit[api]' (out of range for int32)"},
+		{"limit api 1k\n", "invalid value '1k' for 'limit[api]' (expected number)"},
+		{"cost {\n  flour 2.45\n  sugar lots\n}\n", "invalid value 'lots' for 'cost[sugar]' (expected number)"},
+		{"cost {\n  flour 1e39\n}\n", "invalid value '1e39' for 'cost[flour]' (out of range for float32)"},
+		{"counts eggs\n", "syntax error for counts/eggs: value expected"},
+	}
+
+	for _, td := range tests {
+		err := ReadString("test", td.txt, &data)
+
+		pe, ok := err.(*ParseError)
+		if !ok {
+			t.Errorf("expected to fail '%s', got %T: %v", td.txt, err, err)
+			continue
+		}
+		if pe.Err.Error() != td.expect {
+			t.Errorf("expected %q, got %q", td.expect, pe.Err.Error())
+		}
+	}
+}
+
+type level string
+
+func TestGenericMaps(t *testing.T) {
+
+	type stuff struct {
+		Timeout map[string]time.Duration
+		Codes   map[int]string
+		Hosts   map[netip.Addr]string
+		Ports   map[string]uint16
+		Start   map[string]time.Time
+		Colors  map[string]color
+		Levels  map[string]level
+		Routes  map[string][]string
+		Enabled map[uint8]bool
+		Secs    map[string]int64 `ac/convert:"duration"`
+		Set     map[int]struct{}
+	}
+
+	var data stuff
+
+	err := ReadString("test", `
+timeout  read 5s
+timeout  write 1m
+codes    404 "not found"
+codes {
+    500 "server error"
+    0x1F6 teapot
+}
+hosts    10.0.0.1 gizmo
+hosts {
+    "::1"  localhost
+}
+ports    http 80
+start    epoch 1970-01-01T00:00:00Z
+colors   sky blue
+levels   api debug
+routes   default a b c
+enabled  1
+enabled  2 off
+secs {
+    day 1d
+}
+set 1 2 3
+`, &data)
+
+	if err != nil {
+		t.Fatalf("failed: %v", err)
+	}
+
+	if data.Timeout["read"] != 5*time.Second || data.Timeout["write"] != time.Minute {
+		t.Errorf("failed: %+v", data.Timeout)
+	}
+	if data.Codes[404] != "not found" || data.Codes[500] != "server error" || data.Codes[502] != "teapot" {
+		t.Errorf("failed: %+v", data.Codes)
+	}
+	if data.Hosts[netip.MustParseAddr("10.0.0.1")] != "gizmo" || data.Hosts[netip.IPv6Loopback()] != "localhost" {
+		t.Errorf("failed: %+v", data.Hosts)
+	}
+	if data.Ports["http"] != 80 || data.Start["epoch"].Unix() != 0 || data.Colors["sky"] != "blue" || data.Levels["api"] != "debug" {
+		t.Errorf("failed: %+v", data)
+	}
+	if len(data.Routes["default"]) != 3 || data.Routes["default"][2] != "c" {
+		t.Errorf("failed: %+v", data.Routes)
+	}
+	if v, ok := data.Enabled[2]; !ok || v || !data.Enabled[1] {
+		t.Errorf("failed: %+v", data.Enabled)
+	}
+	if data.Secs["day"] != 86400 || len(data.Set) != 3 {
+		t.Errorf("failed: %+v", data)
+	}
+
+	fails := []string{
+		"timeout read 5 seconds\n",
+		"codes abc xyz\n",
+		"hosts 10.0.0.256 gizmo\n",
+		"ports http 65536\n",
+		"colors sky purple\n",
+		"enabled 256\n",
+		"set 1 two\n",
+		"codes 404\n",
+	}
+
+	for _, txt := range fails {
+		err := ReadString("test", txt, &data)
+		if err == nil {
+			t.Errorf("expected to fail: '%s'", txt)
+		}
+	}
+}
+
+func TestStructMap(t *testing.T) {
+
+	type upstream struct {
+		Host string
+		Port int `ac/default:"80"`
+		Tag  []string
+	}
+
+	type stuff struct {
+		Upstream map[string]*upstream
+		Backend  map[string]upstream
+		Weight   map[int]*upstream
+		Start    map[string]time.Time
+	}
+
+	txt := `
+upstream primary {
+    host vorpalsword
+    port 8080
+}
+upstream "secondary" {
+    host jubjubtree
+}
+backend api {
+    host momerath
+    tag  a
+}
+weight 10 {
+    host borogrove
+}
+start {
+    epoch 1970-01-01T00:00:00Z
+}
+`
+
+	var data stuff
+
+	err := ReadString("test", txt, &data)
+	if err != nil {
+		t.Fatalf("failed: %v", err)
+	}
+
+	if len(data.Upstream) != 2 || data.Upstream["primary"].Port != 8080 || data.Upstream["secondary"].Host != "jubjubtree" || data.Upstream["secondary"].Port != 80 {
+		t.Errorf("failed: %+v", data.Upstream)
+	}
+	if data.Backend["api"].Host != "momerath" || data.Backend["api"].Port != 80 {
+		t.Errorf("failed: %+v", data.Backend)
+	}
+	if data.Weight[10].Host != "borogrove" || data.Start["epoch"].Unix() != 0 {
+		t.Errorf("failed: %+v", data)
+	}
+
+	dup := txt + `
+backend api {
+    port 8080
+    tag  b
+}
+`
+	data = stuff{}
+	err = ReadString("test", dup, &data)
+
+	pe, ok := err.(*ParseError)
+	if !ok || pe.Line != 20 || pe.Err.Error() != "duplicate section 'backend api'" {
+		t.Errorf("expected to fail, got %T: %v", err, err)
+	}
+
+	data = stuff{}
+	err = New(WithMergeBlocks(true)).ReadString("test", dup, &data)
+	if err != nil {
+		t.Fatalf("failed: %v", err)
+	}
+
+	if b := data.Backend["api"]; b.Host != "momerath" || b.Port != 8080 || len(b.Tag) != 2 {
+		t.Errorf("failed to merge: %+v", data.Backend)
+	}
+
+	fails := []string{
+		"upstream {\n}\n",
+		"weight heavy {\n}\n",
+		"upstream primary {\n    color purple\n}\n",
+		"start epoch {\n}\n",
+	}
+
+	for _, txt := range fails {
+		err := ReadString("test", txt, &data)
+		if err == nil {
+			t.Errorf("expected to fail: '%s'", txt)
+		}
+	}
+}
+
+func TestLabeledBlocks(t *testing.T) {
+
+	type thing struct {
+		Name string `ac/label:"" ac/required:"" ac/default:"unnamed"`
+		Size int
+	}
+	type upstream struct {
+		Name string `ac/label:""`
+		Host string
+	}
+	type other struct {
+		Size int
+	}
+
+	type stuff struct {
+		Thing    []*thing
+		Upstream map[string]*upstream
+		Other    []*other
+		Param    other
+	}
+
+	var data stuff
+
+	err := ReadString("test", `
+thing momerath {
+    size 123
+}
+thing {
+    name vorpalsword
+}
+thing "jubjub tree" {
+}
+upstream primary {
+    host borogrove
+}
+other {
+    size 1
+}
+`, &data)
+
+	if err != nil {
+		t.Fatalf("failed: %v", err)
+	}
+
+	if len(data.Thing) != 3 || data.Thing[0].Name != "momerath" || data.Thing[0].Size != 123 ||
+		data.Thing[1].Name != "vorpalsword" || data.Thing[2].Name != "jubjub tree" {
+		t.Errorf("failed: %+v", data.Thing)
+	}
+	if data.Upstream["primary"].Name != "primary" {
+		t.Errorf("failed to set map label: %+v", data.Upstream)
+	}
+
+	// required label
+	data = stuff{}
+	err = ReadString("test", "thing {\n    size 1\n}\n", &data)
+	pe, ok := err.(*ParseError)
+	if !ok || pe.Err.Error() != "missing required param 'thing[0].name'" {
+		t.Errorf("expected to fail, got %T: %v", err, err)
+	}
+
+	fails := []string{
+		"other gizmo {\n}\n",
+		"param gizmo {\n}\n",
+		"thing gizmo extra {\n}\n",
+	}
+
+	for _, txt := range fails {
+		err := ReadString("test", txt, &data)
+		if err == nil {
+			t.Errorf("expected to fail: '%s'", txt)
+		}
+	}
+}
+
+func TestPointers(t *testing.T) {
+
+	type thing struct {
+		Name string `ac/required:""`
+		Size *int
+	}
+
+	type stuff struct {
+		Retries *int
+		Name    *string
+		Rate    *float64 `ac/max:"1"`
+		Start   *time.Time
+		Tag     *[]string
+		Ports   []*uint16
+		Param   *thing
+		Other   *thing
+		Dotted  *thing
+	}
+
+	dec := New(WithPointers(true))
+
+	var data stuff
+
+	err := dec.ReadString("test", `
+retries 0
+name    gizmo
+start   2024-01-01T02:03:04Z
+tag     lorem ipsum
+ports   80 443
+param {
+    name momerath
+    size 0
+}
+dotted.name vorpalsword
+`, &data)
+
+	if err != nil {
+		t.Fatalf("failed: %v", err)
+	}
+
+	if data.Retries == nil || *data.Retries != 0 || data.Name == nil || *data.Name != "gizmo" {
+		t.Errorf("failed: %+v", data)
+	}
+	if data.Rate != nil || data.Other != nil {
+		t.Errorf("expected nil: %+v", data)
+	}
+	if data.Start == nil || data.Start.Unix() != 1704074584 || data.Tag == nil || len(*data.Tag) != 2 {
+		t.Errorf("failed: %+v", data)
+	}
+	if len(data.Ports) != 2 || *data.Ports[1] != 443 {
+		t.Errorf("failed: %+v", data.Ports)
+	}
+	if data.Param == nil || data.Param.Name != "momerath" || data.Param.Size == nil || *data.Param.Size != 0 {
+		t.Errorf("failed: %+v", data.Param)
+	}
+	if data.Dotted == nil || data.Dotted.Name != "vorpalsword" || data.Dotted.Size != nil {
+		t.Errorf("failed: %+v", data.Dotted)
+	}
+
+	fails := []string{
+		"rate 2\n",
+		"retries many\n",
+		"other {\n    size 1\n}\n",
+	}
+
+	for _, txt := range fails {
+		data = stuff{}
+		err := dec.ReadString("test", txt, &data)
+		if err == nil {
+			t.Errorf("expected to fail: '%s'", txt)
+		}
+	}
+
+	// not enabled
+	data = stuff{}
+	err = ReadString("test", "start 2024-01-01T02:03:04Z\n", &data)
+	if err == nil {
+		t.Errorf("expected to fail: %+v", data)
+	}
+}
+
+func TestStructSlices(t *testing.T) {
+
+	type thing struct {
+		Name string `ac/label:""`
+		Size int    `ac/default:"8080"`
+	}
+
+	type stuff struct {
+		Thing  []thing
+		Pair   [2]*thing
+		Point  [3]int
+		Route  [][]string
+		Ranges [][2]int
+	}
+
+	var data stuff
+
+	err := ReadString("test", `
+thing momerath {
+    size 1
+}
+thing vorpalsword {
+}
+pair {
+    name jubjub
+}
+point 1 2 3
+route a b c
+route d
+ranges 1 2
+ranges 3 4
+`, &data)
+
+	if err != nil {
+		t.Fatalf("failed: %v", err)
+	}
+
+	if len(data.Thing) != 2 || data.Thing[0].Name != "momerath" || data.Thing[0].Size != 1 || data.Thing[1].Size != 8080 {
+		t.Errorf("failed: %+v", data.Thing)
+	}
+	if data.Pair[0] == nil || data.Pair[0].Name != "jubjub" || data.Pair[1] != nil {
+		t.Errorf("failed: %+v", data.Pair)
+	}
+	if data.Point != [3]int{1, 2, 3} {
+		t.Errorf("failed: %+v", data.Point)
+	}
+	if len(data.Route) != 2 || len(data.Route[0]) != 3 || data.Route[0][2] != "c" || len(data.Route[1]) != 1 {
+		t.Errorf("failed: %+v", data.Route)
+	}
+	if len(data.Ranges) != 2 || data.Ranges[1] != [2]int{3, 4} {
+		t.Errorf("failed: %+v", data.Ranges)
+	}
+
+	fails := []string{
+		"point 1 2\n",
+		"point 1 2 3 4\n",
+		"ranges 1 2 3\n",
+		"pair {\n}\npair {\n}\npair {\n}\n",
+	}
+
+	for _, txt := range fails {
+		data = stuff{}
+		err := ReadString("test", txt, &data)
+		if err == nil {
+			t.Errorf("expected to fail: '%s'", txt)
+		}
+	}
+}
+
+type listen struct {
+	Addr string
+	Port int
+	TLS  bool
+}
+
+var errListen = errors.New("expected: addr port [tls]")
+
+func (l *listen) UnmarshalACTokens(vals []string) error {
+
+	if len(vals) < 2 || len(vals) > 3 || len(vals) == 3 && vals[2] != "tls" {
+		return errListen
+	}
+
+	port, err := strconv.Atoi(vals[1])
+	if err != nil {
+		return err
+	}
+
+	*l = listen{vals[0], port, len(vals) == 3}
+	return nil
+}
+
+// not used, UnmarshalACTokens is preferred
+func (l *listen) UnmarshalText(b []byte) error {
+	return errors.New("should not be called")
+}
+
+func TestTokensUnmarshaler(t *testing.T) {
+
+	type stuff struct {
+		Listen  listen
+		Also    []listen
+		Backend map[string]listen
+	}
+
+	var data stuff
+
+	err := ReadString("test", `
+listen 0.0.0.0 8080 tls
+also   127.0.0.1 80
+also   127.0.0.1 81
+backend {
+    primary   10.1.2.3 443 tls
+    secondary 10.1.2.4 443
+}
+`, &data)
+
+	if err != nil {
+		t.Fatalf("failed: %v", err)
+	}
+
+	if data.Listen != (listen{"0.0.0.0", 8080, true}) {
+		t.Errorf("failed: %+v", data.Listen)
+	}
+	if len(data.Also) != 2 || data.Also[1] != (listen{"127.0.0.1", 81, false}) {
+		t.Errorf("failed: %+v", data.Also)
+	}
+	if len(data.Backend) != 2 || data.Backend["primary"] != (listen{"10.1.2.3", 443, true}) || data.Backend["secondary"].TLS {
+		t.Errorf("failed: %+v", data.Backend)
+	}
+
+	data = stuff{}
+	err = ReadString("test", "listen 0.0.0.0 8080 ssl\n", &data)
+	if !errors.Is(err, errListen) {
+		t.Errorf("expected errListen, got %v", err)
+	}
+}
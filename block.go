@@ -0,0 +1,218 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <tcp4me.com!jaw>
+// Created: 2026-Oct-16 15:10 (EDT)
+// Function: custom block unmarshalers
+
+package acconfig
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// BlockUnmarshaler is implemented by types that read their own block:
+//
+//	acl {
+//	    allow 10.0.0.0/8
+//	    deny  all
+//	}
+//
+// anything left unread in the block, when UnmarshalACBlock returns, is skipped
+type BlockUnmarshaler interface {
+	UnmarshalACBlock(d *BlockDecoder) error
+}
+
+// Token is a token from the config file, and where it is
+type Token struct {
+	Text string
+	Pos  Position
+}
+
+// BlockDecoder reads the contents of a block, for a BlockUnmarshaler
+type BlockDecoder struct {
+	c       *conf
+	f       *bufio.Reader
+	name    string
+	label   string
+	pos     position
+	depth   int     // nested blocks
+	open    bool    // the last line read opened a nested block
+	done    bool    // the closing '}' has been read
+	pending []Token // unread tokens of the current line
+	last    position
+	key     string // first token of the last line read
+}
+
+var blockUnmarshalerType = reflect.TypeOf((*BlockUnmarshaler)(nil)).Elem()
+
+// Name is the name of the block
+func (d *BlockDecoder) Name() string {
+	return d.name
+}
+
+// Label is the label of the block, if it has one
+func (d *BlockDecoder) Label() string {
+	return d.label
+}
+
+// Pos is the position of the start of the block
+func (d *BlockDecoder) Pos() Position {
+	return d.position(d.pos)
+}
+
+// Line returns the rest of the current line, or the next non-empty line.
+// lines of nested blocks, including their '{' and '}', are returned as is.
+// io.EOF is returned at the end of the block
+func (d *BlockDecoder) Line() ([]Token, error) {
+
+	if len(d.pending) != 0 {
+		line := d.pending
+		d.pending = nil
+		return line, nil
+	}
+
+	if d.done {
+		return nil, io.EOF
+	}
+
+	c := d.c
+	d.open = false
+
+	for {
+		tok, err := c.readLine(d.f)
+		if err == io.EOF {
+			d.done = true
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, c.parseError(position{c.line, c.col}, d.name, err)
+		}
+		if len(tok) == 0 {
+			continue
+		}
+
+		d.key = tok[0]
+		d.last = c.tokPos[0]
+
+		switch {
+		case tok[0] == "}" && d.depth == 0:
+			d.done = true
+			return nil, io.EOF
+		case tok[0] == "}":
+			d.depth--
+		case tok[len(tok)-1] == "{":
+			d.depth++
+			d.open = true
+		}
+
+		line := make([]Token, len(tok))
+		for i, t := range tok {
+			line[i] = Token{t, d.position(c.tokPos[i])}
+		}
+		return line, nil
+	}
+}
+
+// Token returns the next token in the block.
+// io.EOF is returned at the end of the block
+func (d *BlockDecoder) Token() (Token, error) {
+
+	if len(d.pending) == 0 {
+		line, err := d.Line()
+		if err != nil {
+			return Token{}, err
+		}
+		d.pending = line
+	}
+
+	t := d.pending[0]
+	d.pending = d.pending[1:]
+	d.last = position{t.Pos.Line, t.Pos.Col}
+	return t, nil
+}
+
+// Decode reads the nested block, just opened by the last line read, into the struct
+//
+//	match {
+//	    port 443
+//	}
+func (d *BlockDecoder) Decode(cf interface{}) error {
+
+	if !d.open {
+		return d.Errorf("no block to decode")
+	}
+	if err := isPtrStruct(cf); err != nil {
+		return err
+	}
+
+	c := d.c
+	pos := d.last
+	d.open = false
+	d.pending = nil
+	d.depth--
+
+	defer c.enterBlock(d.key)()
+
+	v := reflect.ValueOf(cf).Elem()
+	if err := c.initStruct(v, c.block); err != nil {
+		return c.parseError(pos, d.key, err)
+	}
+	if err := c.readConfig(d.f, cf, true); err != nil {
+		return err
+	}
+	return c.finishStruct(v, c.block, pos)
+}
+
+// Errorf reports an error at the last token read
+func (d *BlockDecoder) Errorf(format string, args ...interface{}) error {
+	return d.c.parseError(d.last, d.key, fmt.Errorf(format, args...))
+}
+
+func (d *BlockDecoder) position(p position) Position {
+	return Position{d.c.file, p.line, p.col}
+}
+
+// skip discards the rest of the block
+func (d *BlockDecoder) skip() error {
+
+	d.pending = nil
+	for {
+		_, err := d.Line()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// isBlockUnmarshaler checks if the field reads its own blocks
+func isBlockUnmarshaler(fv reflect.Value) bool {
+	return fv.CanAddr() && fv.Addr().Type().Implements(blockUnmarshalerType)
+}
+
+// readCustomBlock reads a block with the field's UnmarshalACBlock
+func (c *conf) readCustomBlock(f *bufio.Reader, sect string, label string, pos position, fv reflect.Value) error {
+
+	c.markSet(joinPath(c.block, sect), fv)
+	defer c.enterBlock(sect)()
+
+	d := &BlockDecoder{
+		c:     c,
+		f:     f,
+		name:  sect,
+		label: label,
+		pos:   pos,
+		last:  pos,
+		key:   sect,
+	}
+
+	err := fv.Addr().Interface().(BlockUnmarshaler).UnmarshalACBlock(d)
+	if err = c.fail(c.parseError(pos, sect, err)); err != nil {
+		return err
+	}
+	return d.skip()
+}
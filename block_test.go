@@ -0,0 +1,175 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <tcp4me.com!jaw>
+// Created: 2026-Oct-16 15:42 (EDT)
+// Function: testing
+
+package acconfig
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+type aclMatch struct {
+	Port  int    `ac/required:""`
+	Proto string `ac/default:"tcp"`
+}
+
+type aclRule struct {
+	Allow bool
+	Addr  string
+	Match *aclMatch
+}
+
+type acl struct {
+	Name  string
+	Rules []aclRule
+}
+
+func (a *acl) UnmarshalACBlock(d *BlockDecoder) error {
+
+	a.Name = d.Label()
+
+	for {
+		line, err := d.Line()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var r aclRule
+		switch line[0].Text {
+		case "allow":
+			r.Allow = true
+		case "deny":
+		case "stop":
+			// the rest of the block is skipped
+			return nil
+		default:
+			return d.Errorf("invalid acl rule '%s'", line[0].Text)
+		}
+
+		if len(line) < 2 {
+			return d.Errorf("missing address")
+		}
+		r.Addr = line[1].Text
+
+		if line[len(line)-1].Text == "{" {
+			r.Match = &aclMatch{}
+			if err := d.Decode(r.Match); err != nil {
+				return err
+			}
+		}
+		a.Rules = append(a.Rules, r)
+	}
+}
+
+// words reads the tokens of its block
+type words []string
+
+func (w *words) UnmarshalACBlock(d *BlockDecoder) error {
+
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if t.Text == "bad" {
+			return d.Errorf("bad word")
+		}
+		*w = append(*w, t.Text)
+	}
+}
+
+func TestBlockUnmarshaler(t *testing.T) {
+
+	type stuff struct {
+		Name  string
+		ACL   acl
+		Words words
+		Size  int
+	}
+
+	var data stuff
+
+	err := ReadString("test", `
+name gizmo
+acl internal {
+    allow 10.0.0.0/8 {
+        port 443
+    }
+    deny  all
+    stop
+    allow 192.168.0.0/16
+    nested {
+        allow all
+    }
+}
+words {
+    lorem ipsum
+    dolor
+}
+size 123
+`, &data)
+
+	if err != nil {
+		t.Fatalf("failed: %v", err)
+	}
+
+	if data.Name != "gizmo" || data.Size != 123 || data.ACL.Name != "internal" {
+		t.Errorf("failed: %+v", data)
+	}
+	if len(data.ACL.Rules) != 2 || !data.ACL.Rules[0].Allow || data.ACL.Rules[1].Addr != "all" {
+		t.Errorf("failed: %+v", data.ACL.Rules)
+	}
+	if m := data.ACL.Rules[0].Match; m == nil || m.Port != 443 || m.Proto != "tcp" {
+		t.Errorf("failed: %+v", m)
+	}
+	if len(data.Words) != 3 || data.Words[2] != "dolor" {
+		t.Errorf("failed: %+v", data.Words)
+	}
+
+	type testdata struct {
+		txt  string
+		line int
+		col  int
+		key  string
+	}
+
+	tests := []testdata{
+		{"acl {\n    allow 10.0.0.0/8\n    permit all\n}\n", 3, 5, "permit"},
+		{"acl {\n    allow\n}\n", 2, 5, "allow"},
+		{"acl {\n    allow all {\n        proto udp\n    }\n}\n", 2, 5, "port"},
+		{"acl {\n    allow all {\n        port http\n    }\n}\n", 3, 9, "port"},
+		{"words {\n    lorem ipsum\n    dolor bad\n}\n", 3, 11, "dolor"},
+	}
+
+	for _, td := range tests {
+		data = stuff{}
+		err := ReadString("test", td.txt, &data)
+
+		var pe *ParseError
+		if !errors.As(err, &pe) {
+			t.Errorf("expected ParseError, got %T: %v", err, err)
+			continue
+		}
+		if pe.Line != td.line || pe.Col != td.col || pe.Key != td.key {
+			t.Errorf("expected %d:%d %s, got %d:%d %s: %v", td.line, td.col, td.key, pe.Line, pe.Col, pe.Key, err)
+		}
+	}
+
+	// the rest of the block is skipped after an error
+	data = stuff{}
+	err = New(WithCollectErrors(true)).ReadString("test", "acl {\n    permit all\n    nested {\n    }\n}\nsize 123\n", &data)
+
+	el, ok := err.(ErrorList)
+	if !ok || len(el) != 1 || data.Size != 123 {
+		t.Errorf("expected 1 error, got %T: %v", err, err)
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <tcp4me.com!jaw>
+// Created: 2026-Oct-16 13:40 (EDT)
+// Function: value constraints
+
+package acconfig
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// checkConstraints checks a newly stored value against the constraint tags:
+//
+//	ac/min:"1"  ac/max:"65535"          numeric values
+//	ac/oneof:"debug info warn error"    any value
+//	ac/match:"^[a-z]+$"                 any value, the text in the config file
+//	ac/len:"1..64"                      length of strings
+//
+// slices and maps are checked by element, as they are stored
+func (c *conf) checkConstraints(cfv reflect.Value, tags reflect.StructTag, k string, v string) error {
+
+	for cfv.Kind() == reflect.Pointer && !cfv.IsNil() {
+		cfv = cfv.Elem()
+	}
+
+	switch cfv.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return nil
+	}
+
+	if lim, ok := c.tag(tags, "min"); ok {
+		cmp, err := c.compare(cfv, tags, lim)
+		if err != nil {
+			return c.constraintError("min", k, err)
+		}
+		if cmp < 0 {
+			return c.violation("min", k, v, "must be at least %s", lim)
+		}
+	}
+
+	if lim, ok := c.tag(tags, "max"); ok {
+		cmp, err := c.compare(cfv, tags, lim)
+		if err != nil {
+			return c.constraintError("max", k, err)
+		}
+		if cmp > 0 {
+			return c.violation("max", k, v, "must be at most %s", lim)
+		}
+	}
+
+	if opts, ok := c.tag(tags, "oneof"); ok {
+		found, err := c.oneOf(cfv, tags, opts)
+		if err != nil {
+			return c.constraintError("oneof", k, err)
+		}
+		if !found {
+			return c.violation("oneof", k, v, "must be one of: %s", opts)
+		}
+	}
+
+	if pat, ok := c.tag(tags, "match"); ok {
+		re, err := c.regexp(pat)
+		if err != nil {
+			return c.constraintError("match", k, err)
+		}
+		if !re.MatchString(v) {
+			return c.violation("match", k, v, "must match '%s'", pat)
+		}
+	}
+
+	if rng, ok := c.tag(tags, "len"); ok {
+		if cfv.Kind() != reflect.String {
+			return c.constraintError("len", k, fmt.Errorf("not supported for %s", cfv.Type()))
+		}
+		lo, hi, err := parseRange(rng)
+		if err != nil {
+			return c.constraintError("len", k, err)
+		}
+		if n := utf8.RuneCountInString(cfv.String()); n < lo || n > hi {
+			return c.violation("len", k, v, "length must be %s", rng)
+		}
+	}
+
+	return nil
+}
+
+// violation reports a value that does not satisfy a constraint
+func (c *conf) violation(tag string, k string, v string, format string, args ...interface{}) error {
+	return fmt.Errorf("invalid value '%s' for '%s': %s (%s%s)", v, k, fmt.Sprintf(format, args...), c.dec.tagPrefix, tag)
+}
+
+// constraintError reports a problem with the constraint itself
+func (c *conf) constraintError(tag string, k string, err error) error {
+	return fmt.Errorf("invalid %s%s for '%s': %w", c.dec.tagPrefix, tag, k, err)
+}
+
+// compare compares the value to a limit, parsed the same as the value would be
+func (c *conf) compare(cfv reflect.Value, tags reflect.StructTag, lim string) (int, error) {
+
+	lv := reflect.New(cfv.Type()).Elem()
+	if err := c.storeField(lv, tags, "", lim, nil); err != nil {
+		return 0, err
+	}
+
+	switch cfv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp3(cfv.Int() < lv.Int(), cfv.Int() > lv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return cmp3(cfv.Uint() < lv.Uint(), cfv.Uint() > lv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return cmp3(cfv.Float() < lv.Float(), cfv.Float() > lv.Float()), nil
+	}
+
+	return 0, fmt.Errorf("not supported for %s", cfv.Type())
+}
+
+func cmp3(lt, gt bool) int {
+
+	switch {
+	case lt:
+		return -1
+	case gt:
+		return 1
+	}
+	return 0
+}
+
+// oneOf checks the value against a list of options, parsed the same as the value would be
+func (c *conf) oneOf(cfv reflect.Value, tags reflect.StructTag, opts string) (bool, error) {
+
+	if !cfv.Type().Comparable() {
+		return false, fmt.Errorf("not supported for %s", cfv.Type())
+	}
+
+	vals, err := splitTokens(opts)
+	if err != nil {
+		return false, err
+	}
+
+	for _, opt := range vals {
+		ov := reflect.New(cfv.Type()).Elem()
+		if err := c.storeField(ov, tags, "", opt, nil); err != nil {
+			return false, err
+		}
+		if ov.Equal(cfv) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (c *conf) regexp(pat string) (*regexp.Regexp, error) {
+
+	c.dec.lock.Lock()
+	defer c.dec.lock.Unlock()
+
+	if re, ok := c.dec.regexps[pat]; ok {
+		return re, nil
+	}
+
+	re, err := regexp.Compile(pat)
+	if err != nil {
+		return nil, err
+	}
+
+	c.dec.regexps[pat] = re
+	return re, nil
+}
+
+// parseRange parses "lo..hi", "lo..", "..hi", or "n"
+func parseRange(rng string) (int, int, error) {
+
+	var err error
+	lo, hi := 0, int(^uint(0)>>1)
+
+	los, his, isRange := strings.Cut(rng, "..")
+	if !isRange {
+		his = los
+	}
+
+	if los != "" {
+		lo, err = strconv.Atoi(strings.TrimSpace(los))
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid range '%s'", rng)
+		}
+	}
+	if his != "" {
+		hi, err = strconv.Atoi(strings.TrimSpace(his))
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid range '%s'", rng)
+		}
+	}
+
+	return lo, hi, nil
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <tcp4me.com!jaw>
+// Created: 2026-Oct-16 14:02 (EDT)
+// Function: testing
+
+package acconfig
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConstraints(t *testing.T) {
+
+	type stuff struct {
+		Port    int           `ac/min:"1" ac/max:"65535"`
+		Rate    float64       `ac/min:"0.5"`
+		Level   string        `ac/oneof:"debug info warn error"`
+		Mode    int           `ac/oneof:"0x1 2 4"`
+		Name    string        `ac/match:"^[a-z]+$" ac/len:"1..8"`
+		Code    string        `ac/len:"3"`
+		Tag     []string      `ac/len:"..4"`
+		Ports   []int         `ac/max:"1024"`
+		Timeout int64         `ac/convert:"duration" ac/max:"1h"`
+		Elapsed time.Duration `ac/min:"1s"`
+	}
+
+	type testdata struct {
+		shouldFail bool
+		txt        string
+		expect     string
+	}
+
+	tests := []testdata{
+		{false, "port 1\nport 65535\nrate 0.5\n", ""},
+		{true, "port 0\n", "invalid value '0' for 'port': must be at least 1 (ac/min)"},
+		{true, "port 65536\n", "invalid value '65536' for 'port': must be at most 65535 (ac/max)"},
+		{true, "rate 0.25\n", "invalid value '0.25' for 'rate': must be at least 0.5 (ac/min)"},
+		{false, "level warn\nmode 1\nmode 4\n", ""},
+		{true, "level verbose\n", "invalid value 'verbose' for 'level': must be one of: debug info warn error (ac/oneof)"},
+		{true, "mode 3\n", "invalid value '3' for 'mode': must be one of: 0x1 2 4 (ac/oneof)"},
+		{false, "name gizmo\ncode abc\n", ""},
+		{true, "name Gizmo\n", "invalid value 'Gizmo' for 'name': must match '^[a-z]+$' (ac/match)"},
+		{true, "name abcdefghi\n", "invalid value 'abcdefghi' for 'name': length must be 1..8 (ac/len)"},
+		{true, "code abcd\n", "invalid value 'abcd' for 'code': length must be 3 (ac/len)"},
+		{false, "tag abc abcd\nports 80 443\n", ""},
+		{true, "tag abc abcde\n", "invalid value 'abcde' for 'tag': length must be ..4 (ac/len)"},
+		{true, "ports 80 8080\n", "invalid value '8080' for 'ports': must be at most 1024 (ac/max)"},
+		{false, "timeout 1h\nelapsed 1s\n", ""},
+		{true, "timeout 2h\n", "invalid value '2h' for 'timeout': must be at most 1h (ac/max)"},
+		{true, "elapsed 999ms\n", "invalid value '999ms' for 'elapsed': must be at least 1s (ac/min)"},
+	}
+
+	for _, td := range tests {
+		var data stuff
+		err := ReadString("test", td.txt, &data)
+
+		if !td.shouldFail {
+			if err != nil {
+				t.Errorf("failed '%s': %v", td.txt, err)
+			}
+			continue
+		}
+
+		pe, ok := err.(*ParseError)
+		if !ok {
+			t.Errorf("expected to fail '%s', got %T: %v", td.txt, err, err)
+			continue
+		}
+		if pe.Err.Error() != td.expect {
+			t.Errorf("expected %q, got %q", td.expect, pe.Err.Error())
+		}
+	}
+}
+
+func TestBadConstraints(t *testing.T) {
+
+	type stuff1 struct {
+		Name string `ac/min:"1"`
+	}
+	type stuff2 struct {
+		Name string `ac/match:"[a-z"`
+	}
+	type stuff3 struct {
+		Size int `ac/len:"1..x"`
+	}
+
+	var data1 stuff1
+	if err := ReadString("test", "name gizmo\n", &data1); err == nil {
+		t.Errorf("expected to fail: %+v", data1)
+	}
+
+	var data2 stuff2
+	if err := ReadString("test", "name gizmo\n", &data2); err == nil {
+		t.Errorf("expected to fail: %+v", data2)
+	}
+
+	var data3 stuff3
+	if err := ReadString("test", "size 1\n", &data3); err == nil {
+		t.Errorf("expected to fail: %+v", data3)
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <tcp4me.com!jaw>
+// Created: 2026-Oct-16 17:20 (EDT)
+// Function: ac/convert converters
+
+package acconfig
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sync"
+)
+
+// Converter parses a value for fields tagged `ac/convert:"name"`.
+// the result is stored into the field (or the slice or map element).
+// numeric results are converted to the type of the field, if they fit
+type Converter func(string) (interface{}, error)
+
+var (
+	convLock   sync.RWMutex
+	converters = make(map[string]Converter)
+)
+
+// builtin conversions, for integer fields
+var builtinConverters = map[string]bool{
+	"duration": true,
+	"size":     true,
+}
+
+// RegisterConverter registers a converter for all Decoders.
+// it overrides any builtin converter with the same name
+func RegisterConverter(name string, fn Converter) {
+
+	convLock.Lock()
+	defer convLock.Unlock()
+	converters[name] = fn
+}
+
+// converter finds the registered converter
+func (c *conf) converter(name string) Converter {
+
+	if fn, ok := c.dec.converters[name]; ok {
+		return fn
+	}
+
+	convLock.RLock()
+	defer convLock.RUnlock()
+	return converters[name]
+}
+
+// storeConverted stores the value using the field's converter, if it has one.
+// done is false if the field has none, or if the field is a slice, map, or pointer,
+// and the converted value is not the whole field, but is for its elements
+func (c *conf) storeConverted(cfv reflect.Value, tags reflect.StructTag, k string, v string) (done bool, err error) {
+
+	conv, ok := c.tag(tags, "convert")
+	if !ok {
+		return false, nil
+	}
+
+	fn := c.converter(conv)
+	if fn == nil {
+		if builtinConverters[conv] {
+			return false, nil
+		}
+		return true, fmt.Errorf("unknown converter '%s' for '%s'", conv, k)
+	}
+
+	if cfv.Kind() == reflect.Map {
+		// the value is the key, convert the map values as they are stored
+		return false, nil
+	}
+
+	x, err := fn(v)
+	if err != nil {
+		return true, fmt.Errorf("invalid value '%s' for '%s' (expected %s): %w", v, k, conv, err)
+	}
+
+	xv := reflect.ValueOf(x)
+	if !xv.IsValid() {
+		return true, fmt.Errorf("converter '%s' returned nil for '%s'", conv, k)
+	}
+
+	if xv.Type().AssignableTo(cfv.Type()) {
+		cfv.Set(xv)
+		return true, nil
+	}
+
+	if isBytes(cfv.Type()) && xv.Type().ConvertibleTo(cfv.Type()) {
+		// eg. string to []byte
+		cfv.Set(xv.Convert(cfv.Type()))
+		return true, nil
+	}
+
+	switch cfv.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Pointer:
+		// not the whole value, convert each element, or *T
+		return false, nil
+	}
+
+	if isNumber(xv.Kind()) && isNumber(cfv.Kind()) {
+		if !setNumber(cfv, xv) {
+			return true, fmt.Errorf("invalid value '%s' for '%s' (cannot convert %v to %s)", v, k, x, cfv.Type())
+		}
+		return true, nil
+	}
+
+	if xv.Kind() == cfv.Kind() && xv.Type().ConvertibleTo(cfv.Type()) {
+		// eg. string to a named string type
+		cfv.Set(xv.Convert(cfv.Type()))
+		return true, nil
+	}
+
+	return true, fmt.Errorf("converter '%s' returned %s, cannot use as %s for '%s'", conv, xv.Type(), cfv.Type(), k)
+}
+
+// hasConverter checks if the field has a registered converter
+func (c *conf) hasConverter(tags reflect.StructTag) bool {
+
+	conv, ok := c.tag(tags, "convert")
+	return ok && c.converter(conv) != nil
+}
+
+func isBytes(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8
+}
+
+func isNumber(k reflect.Kind) bool {
+
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// setNumber stores a number into a numeric field of a different type.
+// it returns false if the number does not fit
+func setNumber(cfv reflect.Value, xv reflect.Value) bool {
+
+	switch xv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x := xv.Int()
+		switch {
+		case cfv.CanInt():
+			if cfv.OverflowInt(x) {
+				return false
+			}
+			cfv.SetInt(x)
+		case cfv.CanUint():
+			if x < 0 || cfv.OverflowUint(uint64(x)) {
+				return false
+			}
+			cfv.SetUint(uint64(x))
+		default:
+			cfv.SetFloat(float64(x))
+		}
+
+	case reflect.Float32, reflect.Float64:
+		x := xv.Float()
+		switch {
+		case cfv.CanFloat():
+			if cfv.OverflowFloat(x) {
+				return false
+			}
+			cfv.SetFloat(x)
+		case x != math.Trunc(x):
+			// not an integer
+			return false
+		case cfv.CanInt():
+			if x < math.MinInt64 || x >= math.MaxInt64 || cfv.OverflowInt(int64(x)) {
+				return false
+			}
+			cfv.SetInt(int64(x))
+		default:
+			if x < 0 || x >= math.MaxUint64 || cfv.OverflowUint(uint64(x)) {
+				return false
+			}
+			cfv.SetUint(uint64(x))
+		}
+
+	default:
+		x := xv.Uint()
+		switch {
+		case cfv.CanInt():
+			if x > math.MaxInt64 || cfv.OverflowInt(int64(x)) {
+				return false
+			}
+			cfv.SetInt(int64(x))
+		case cfv.CanUint():
+			if cfv.OverflowUint(x) {
+				return false
+			}
+			cfv.SetUint(x)
+		default:
+			cfv.SetFloat(float64(x))
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <tcp4me.com!jaw>
+// Created: 2026-Oct-16 17:45 (EDT)
+// Function: testing
+
+package acconfig
+
+import (
+	"encoding/hex"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var errPercent = errors.New("expected a percentage")
+
+func parsePercent(v string) (interface{}, error) {
+
+	n, ok := strings.CutSuffix(v, "%")
+	if !ok {
+		return nil, errPercent
+	}
+	x, err := strconv.ParseFloat(n, 64)
+	if err != nil {
+		return nil, errPercent
+	}
+	return x / 100, nil
+}
+
+func init() {
+	RegisterConverter("test-octal", func(v string) (interface{}, error) {
+		return strconv.ParseUint(v, 8, 64)
+	})
+	RegisterConverter("test-hexbytes", func(v string) (interface{}, error) {
+		return hex.DecodeString(v)
+	})
+	RegisterConverter("test-upper", func(v string) (interface{}, error) {
+		return strings.ToUpper(v), nil
+	})
+}
+
+func TestConverters(t *testing.T) {
+
+	type stuff struct {
+		Rate    float64            `ac/convert:"percent" ac/max:"100%"`
+		Rates   []float32          `ac/convert:"percent"`
+		Weight  map[string]float64 `ac/convert:"percent"`
+		Mode    os.FileMode        `ac/convert:"test-octal" ac/default:"644"`
+		Umask   uint8              `ac/convert:"test-octal"`
+		Key     []byte             `ac/convert:"test-hexbytes"`
+		Keys    [][]byte           `ac/convert:"test-hexbytes"`
+		Text    []byte             `ac/convert:"test-upper"`
+		Perms   []uint16           `ac/convert:"test-octal"`
+		Codes   [2]string          `ac/convert:"test-upper"`
+		Level   *level             `ac/convert:"test-upper"`
+		Timeout int                `ac/convert:"duration"`
+	}
+
+	dec := New(WithConverter("percent", parsePercent), WithPointers(true))
+
+	var data stuff
+
+	err := dec.ReadString("test", `
+rate    12.5%
+rates   10% 20%
+weight  alice 25%
+weight {
+    bob   75%
+}
+umask   022
+key     deadbeef
+keys    cafe f00d
+text    lorem
+perms   644 755
+codes   us ca
+level   warn
+timeout 1h
+`, &data)
+
+	if err != nil {
+		t.Fatalf("failed: %v", err)
+	}
+
+	if data.Rate != 0.125 || len(data.Rates) != 2 || data.Rates[1] != 0.2 || data.Weight["alice"] != 0.25 || data.Weight["bob"] != 0.75 {
+		t.Errorf("failed: %+v", data)
+	}
+	if data.Mode != 0644 || data.Umask != 022 || data.Timeout != 3600 {
+		t.Errorf("failed: %+v", data)
+	}
+	if hex.EncodeToString(data.Key) != "deadbeef" || len(data.Keys) != 2 || hex.EncodeToString(data.Keys[1]) != "f00d" || string(data.Text) != "LOREM" {
+		t.Errorf("failed: %+v %+v %+v", data.Key, data.Keys, data.Text)
+	}
+	if len(data.Perms) != 2 || data.Perms[1] != 0755 || data.Codes != [2]string{"US", "CA"} || data.Level == nil || *data.Level != "WARN" {
+		t.Errorf("failed: %+v", data)
+	}
+
+	type testdata struct {
+		txt    string
+		expect string
+	}
+
+	tests := []testdata{
+		{"rate 12.5\n", "invalid value '12.5' for 'rate' (expected percent): expected a percentage"},
+		{"rate 120%\n", "invalid value '120%' for 'rate': must be at most 100% (ac/max)"},
+		{"rates 10% 20\n", "invalid value '20' for 'rates' (expected percent): expected a percentage"},
+		{"weight {\n    carol 1\n}\n", "invalid value '1' for 'weight[carol]' (expected percent): expected a percentage"},
+		{"umask 777\n", "invalid value '777' for 'umask' (cannot convert 511 to uint8)"},
+		{"key xyz\n", "invalid value 'xyz' for 'key' (expected test-hexbytes): encoding/hex: invalid byte: U+0078 'x'"},
+		{"keys cafe xyz\n", "invalid value 'xyz' for 'keys' (expected test-hexbytes): encoding/hex: invalid byte: U+0078 'x'"},
+		{"weight carol 1\n", "invalid value '1' for 'weight[carol]' (expected percent): expected a percentage"},
+		{"perms 644 9\n", "invalid value '9' for 'perms' (expected test-octal): strconv.ParseUint: parsing \"9\": invalid syntax"},
+	}
+
+	for _, td := range tests {
+		data = stuff{}
+		err := dec.ReadString("test", td.txt, &data)
+
+		pe, ok := err.(*ParseError)
+		if !ok {
+			t.Errorf("expected ParseError, got %T: %v", err, err)
+			continue
+		}
+		if pe.Err.Error() != td.expect {
+			t.Errorf("expected %q, got %q", td.expect, pe.Err.Error())
+		}
+	}
+
+	data = stuff{}
+	err = dec.ReadString("test", "rate 50\n", &data)
+	if !errors.Is(err, errPercent) {
+		t.Errorf("expected errPercent, got %v", err)
+	}
+
+	// not registered with this decoder
+	data = stuff{}
+	err = ReadString("test", "rate 50%\n", &data)
+	if err == nil || !strings.Contains(err.Error(), "unknown converter 'percent' for 'rate'") {
+		t.Errorf("expected unknown converter, got %v", err)
+	}
+}
+
+func TestConverterOverride(t *testing.T) {
+
+	type stuff struct {
+		Timeout int `ac/convert:"duration"`
+	}
+
+	var data stuff
+
+	dec := New(WithConverter("duration", func(v string) (interface{}, error) {
+		return strconv.Atoi(strings.TrimSuffix(v, "ms"))
+	}))
+
+	err := dec.ReadString("test", "timeout 250ms\n", &data)
+	if err != nil || data.Timeout != 250 {
+		t.Errorf("failed: %+v %v", data, err)
+	}
+}
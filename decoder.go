@@ -0,0 +1,203 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <tcp4me.com!jaw>
+// Created: 2026-Oct-16 10:12 (EDT)
+// Function: configurable decoder
+
+package acconfig
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Decoder reads config files into structs.
+// it caches information about the structs it has seen,
+// and may be reused (concurrently) for many files.
+// a Decoder must be created with New, the zero value is not usable
+type Decoder struct {
+	strict          bool
+	collect         bool
+	merge           bool
+	pointers        bool
+	negDurations    bool
+	legacyDurations bool
+	incPath         []string
+	maxDepth        int
+	tagPrefix       string
+	types           map[reflect.Type]func(string) (interface{}, error)
+	converters      map[string]Converter
+	lookupEnv       func(string) (string, bool)
+
+	lock    sync.Mutex
+	info    map[reflect.Type]fieldInfo
+	regexps map[string]*regexp.Regexp
+}
+
+// Option configures a Decoder
+type Option func(*Decoder)
+
+const defaultMaxDepth = 16
+
+// New creates a new Decoder
+func New(opts ...Option) *Decoder {
+
+	d := &Decoder{
+		strict:     true,
+		maxDepth:   defaultMaxDepth,
+		tagPrefix:  "ac/",
+		types:      make(map[reflect.Type]func(string) (interface{}, error)),
+		converters: make(map[string]Converter),
+		lookupEnv:  os.LookupEnv,
+		info:       make(map[reflect.Type]fieldInfo),
+		regexps:    make(map[string]*regexp.Regexp),
+	}
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	return d
+}
+
+// WithStrict controls the handling of unknown params and sections.
+// if strict (the default) they are an error, otherwise they are ignored
+func WithStrict(strict bool) Option {
+	return func(d *Decoder) {
+		d.strict = strict
+	}
+}
+
+// WithCollectErrors controls what happens after an error.
+// if collecting, the decoder keeps going after recoverable errors,
+// and returns all of them in an ErrorList. otherwise (the default)
+// it stops at the first error
+func WithCollectErrors(collect bool) Option {
+	return func(d *Decoder) {
+		d.collect = collect
+	}
+}
+
+// WithMergeBlocks controls what happens when a labeled block is repeated.
+// if merging, the later block adds to the earlier one. otherwise (the default)
+// it is an error
+func WithMergeBlocks(merge bool) Option {
+	return func(d *Decoder) {
+		d.merge = merge
+	}
+}
+
+// WithPointers permits pointer fields (*T). they are left nil unless
+// they are in the config file, so "not configured" can be told apart
+// from "configured as zero". otherwise (the default) they are an error
+func WithPointers(enable bool) Option {
+	return func(d *Decoder) {
+		d.pointers = enable
+	}
+}
+
+// WithNegativeDurations permits negative values for `ac/convert:"duration"`
+func WithNegativeDurations(ok bool) Option {
+	return func(d *Decoder) {
+		d.negDurations = ok
+	}
+}
+
+// WithLegacyDurations selects the original `ac/convert:"duration"` grammar:
+// a number, with an optional y, m (months, of 28 days), d, or h suffix
+func WithLegacyDurations(legacy bool) Option {
+	return func(d *Decoder) {
+		d.legacyDurations = legacy
+	}
+}
+
+// WithIncludePath adds directories to search for included files
+// that are not found relative to the including file
+func WithIncludePath(dirs ...string) Option {
+	return func(d *Decoder) {
+		d.incPath = append(d.incPath, dirs...)
+	}
+}
+
+// WithMaxIncludeDepth limits how deeply include files may nest
+func WithMaxIncludeDepth(n int) Option {
+	return func(d *Decoder) {
+		d.maxDepth = n
+	}
+}
+
+// WithTagPrefix changes the struct tag prefix (default "ac/")
+func WithTagPrefix(prefix string) Option {
+	return func(d *Decoder) {
+		d.tagPrefix = prefix
+	}
+}
+
+// WithLookupEnv sets the function used to expand ${VAR} in values
+// (default os.LookupEnv). nil disables expansion
+func WithLookupEnv(fn func(string) (string, bool)) Option {
+	return func(d *Decoder) {
+		d.lookupEnv = fn
+	}
+}
+
+// WithType registers a function to parse values of the same type as sample.
+// the function should return a value of that type
+func WithType(sample interface{}, fn func(string) (interface{}, error)) Option {
+	return func(d *Decoder) {
+		d.types[reflect.TypeOf(sample)] = fn
+	}
+}
+
+// WithConverter registers a converter for this Decoder.
+// it overrides any global or builtin converter with the same name
+func WithConverter(name string, fn Converter) Option {
+	return func(d *Decoder) {
+		d.converters[name] = fn
+	}
+}
+
+// Read reads a config file into the struct
+func (d *Decoder) Read(file string, cf interface{}) error {
+
+	c := &conf{
+		file: file,
+		dec:  d,
+	}
+
+	return c.readFile(cf)
+}
+
+// ReadFS reads a config file from fsys into the struct
+// included files are also read from fsys
+func (d *Decoder) ReadFS(fsys fs.FS, file string, cf interface{}) error {
+
+	c := &conf{
+		file: file,
+		fsys: fsys,
+		dec:  d,
+	}
+
+	return c.readFile(cf)
+}
+
+// ReadReader reads config from r into the struct
+// name is used in error messages, and to locate included files
+func (d *Decoder) ReadReader(name string, r io.Reader, cf interface{}) error {
+
+	c := &conf{
+		file: name,
+		dec:  d,
+	}
+
+	return c.read(r, cf)
+}
+
+// ReadString reads config from the string into the struct
+func (d *Decoder) ReadString(name string, s string, cf interface{}) error {
+	return d.ReadReader(name, strings.NewReader(s), cf)
+}
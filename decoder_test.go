@@ -0,0 +1,186 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <tcp4me.com!jaw>
+// Created: 2026-Oct-16 10:41 (EDT)
+// Function: testing
+
+package acconfig
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLenient(t *testing.T) {
+
+	type stuff struct {
+		Name string
+		Size int
+	}
+
+	txt := `name gizmo
+color purple
+extra {
+    param 123
+    nested {
+        param 234
+    }
+}
+size 123
+`
+	var data stuff
+
+	err := ReadString("test", txt, &data)
+	if err == nil {
+		t.Errorf("expected to fail: %+v", data)
+	}
+
+	data = stuff{}
+	dec := New(WithStrict(false))
+	err = dec.ReadString("test", txt, &data)
+
+	if err != nil {
+		t.Fatalf("failed: %v", err)
+	}
+	if data.Name != "gizmo" || data.Size != 123 {
+		t.Errorf("failed: got %+v", data)
+	}
+}
+
+func TestTagPrefix(t *testing.T) {
+
+	type stuff struct {
+		Name string `cfg/name:"label"`
+		Size int    `ac/name:"girth"`
+	}
+
+	var data stuff
+
+	dec := New(WithTagPrefix("cfg/"))
+	err := dec.ReadString("test", "label gizmo\nsize 123\n", &data)
+
+	if err != nil {
+		t.Fatalf("failed: %v", err)
+	}
+	if data.Name != "gizmo" || data.Size != 123 {
+		t.Errorf("failed: got %+v", data)
+	}
+}
+
+func TestIncludePath(t *testing.T) {
+
+	type stuff struct {
+		Name string
+		Size int
+	}
+
+	fsys := fstest.MapFS{
+		"etc/main.conf":   {Data: []byte("name gizmo\ninclude common.conf\n")},
+		"lib/common.conf": {Data: []byte("size 123\n")},
+	}
+
+	var data stuff
+
+	err := ReadFS(fsys, "etc/main.conf", &data)
+	if err == nil {
+		t.Errorf("expected to fail: %+v", data)
+	}
+
+	dec := New(WithIncludePath("lib"))
+	err = dec.ReadFS(fsys, "etc/main.conf", &data)
+
+	if err != nil {
+		t.Fatalf("failed: %v", err)
+	}
+	if data.Name != "gizmo" || data.Size != 123 {
+		t.Errorf("failed: got %+v", data)
+	}
+}
+
+func TestMaxIncludeDepth(t *testing.T) {
+
+	type stuff struct {
+		Name string
+	}
+
+	fsys := fstest.MapFS{
+		"loop.conf": {Data: []byte("name gizmo\ninclude loop.conf\n")},
+		"a.conf":    {Data: []byte("include b.conf\n")},
+		"b.conf":    {Data: []byte("name gizmo\n")},
+	}
+
+	var data stuff
+
+	err := ReadFS(fsys, "loop.conf", &data)
+	if err == nil || !strings.Contains(err.Error(), "too many nested includes") {
+		t.Errorf("expected to fail: %v", err)
+	}
+
+	err = New(WithMaxIncludeDepth(1)).ReadFS(fsys, "a.conf", &data)
+	if err != nil {
+		t.Errorf("failed: %v", err)
+	}
+
+	err = New(WithMaxIncludeDepth(0)).ReadFS(fsys, "a.conf", &data)
+	if err == nil {
+		t.Errorf("expected to fail")
+	}
+}
+
+type point struct {
+	X, Y int
+}
+
+func TestWithType(t *testing.T) {
+
+	type stuff struct {
+		Origin point
+		Path   []point
+	}
+
+	parsePoint := func(s string) (interface{}, error) {
+		var p point
+		_, err := fmt.Sscanf(s, "%d,%d", &p.X, &p.Y)
+		return p, err
+	}
+
+	var data stuff
+
+	dec := New(WithType(point{}, parsePoint))
+	err := dec.ReadString("test", "origin 1,2\npath 3,4 5,6\n", &data)
+
+	if err != nil {
+		t.Fatalf("failed: %v", err)
+	}
+	if data.Origin != (point{1, 2}) || len(data.Path) != 2 || data.Path[1] != (point{5, 6}) {
+		t.Errorf("failed: got %+v", data)
+	}
+
+	err = dec.ReadString("test", "origin 1-2\n", &data)
+	if err == nil {
+		t.Errorf("expected to fail")
+	}
+}
+
+func TestDecoderCache(t *testing.T) {
+
+	type stuff struct {
+		Name string
+	}
+
+	dec := New()
+
+	for i := 0; i < 3; i++ {
+		var data stuff
+		err := dec.ReadString("test", "name gizmo\n", &data)
+		if err != nil || data.Name != "gizmo" {
+			t.Errorf("failed: %v %+v", err, data)
+		}
+	}
+
+	if _, ok := dec.info[reflect.TypeOf(stuff{})]; !ok || len(dec.info) != 1 {
+		t.Errorf("expected cached field info: %+v", dec.info)
+	}
+}
@@ -0,0 +1,233 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <tcp4me.com!jaw>
+// Created: 2026-Oct-16 18:10 (EDT)
+// Function: durations
+
+package acconfig
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// time.Duration is great for short durations (microsecs)
+// but useless for real-world durations
+// NB: days, weeks, months, and years are based on "typical" values and not exact
+var durationUnits = map[string]uint64{
+	"ns": 1,
+	"us": 1e3,
+	"µs": 1e3,
+	"ms": 1e6,
+	"s":  1e9,
+	"m":  60e9,
+	"h":  3600e9,
+	"d":  24 * 3600e9,
+	"w":  7 * 24 * 3600e9,
+	"mo": 30 * 24 * 3600e9,
+	"y":  365 * 24 * 3600e9,
+}
+
+// sumDuration adds up a duration: one or more numbers, each with a unit,
+// eg. 90s, 1h30m, 1.5d, 2w, 6mo, 1y. a number without a unit is seconds.
+// returns the exact number of nanoseconds
+func sumDuration(v string) (*big.Rat, error) {
+
+	s := v
+	neg := false
+
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	if s == "" {
+		return nil, errors.New("missing number")
+	}
+	if strings.IndexFunc(s, unicode.IsLetter) == -1 {
+		// plain number
+		s += "s"
+	}
+
+	total := new(big.Rat)
+
+	for s != "" {
+		i := strings.IndexFunc(s, isNotDecimal)
+		if i == 0 {
+			return nil, errors.New("missing number")
+		}
+		if i == -1 {
+			return nil, errors.New("missing unit")
+		}
+		num := s[:i]
+		s = s[i:]
+
+		j := strings.IndexFunc(s, isDecimal)
+		if j == -1 {
+			j = len(s)
+		}
+		unit := s[:j]
+		s = s[j:]
+
+		mult, ok := durationUnits[strings.ToLower(unit)]
+		if !ok {
+			return nil, fmt.Errorf("unknown unit '%s'", unit)
+		}
+
+		if num == "." || strings.Count(num, ".") > 1 {
+			return nil, fmt.Errorf("invalid number '%s'", num)
+		}
+		// eg. .5 or 2.
+		x, ok := new(big.Rat).SetString("0" + strings.TrimSuffix(num, "."))
+		if !ok {
+			return nil, fmt.Errorf("invalid number '%s'", num)
+		}
+
+		x.Mul(x, new(big.Rat).SetUint64(mult))
+		total.Add(total, x)
+	}
+
+	if neg {
+		total.Neg(total)
+	}
+	return total, nil
+}
+
+// parseDuration parses a duration. returns nanoseconds, any fraction is dropped.
+// strconv.ErrRange is returned if it does not fit
+func parseDuration(v string) (int64, error) {
+
+	r, err := sumDuration(v)
+	if err != nil {
+		return 0, err
+	}
+
+	return toInt64(new(big.Int).Quo(r.Num(), r.Denom()))
+}
+
+var errNotWholeSeconds = errors.New("not a whole number of seconds")
+
+// parseDurationSeconds parses a duration. returns seconds.
+// strconv.ErrRange is returned if it does not fit
+func parseDurationSeconds(v string) (int64, error) {
+
+	r, err := sumDuration(v)
+	if err != nil {
+		return 0, err
+	}
+
+	r.Quo(r, big.NewRat(int64(time.Second), 1))
+	if !r.IsInt() {
+		return 0, errNotWholeSeconds
+	}
+	return toInt64(r.Num())
+}
+
+func toInt64(x *big.Int) (int64, error) {
+
+	if !x.IsInt64() {
+		return 0, strconv.ErrRange
+	}
+	return x.Int64(), nil
+}
+
+func isDecimal(r rune) bool {
+	return r == '.' || r >= '0' && r <= '9'
+}
+
+func isNotDecimal(r rune) bool {
+	return !isDecimal(r)
+}
+
+// parseLegacyDuration is the original duration grammar, for WithLegacyDurations:
+// a number, with an optional y, m (28 days), d, or h suffix. returns seconds
+func parseLegacyDuration(v string) (int64, error) {
+
+	if v == "" {
+		return 0, errors.New("missing number")
+	}
+
+	var lc = v[len(v)-1]
+	var i int64
+	var err error
+
+	if lc >= '0' && lc <= '9' {
+		i, err = strconv.ParseInt(v, 0, 32)
+	} else {
+		i, err = strconv.ParseInt(v[0:len(v)-1], 0, 32)
+
+		switch unicode.ToLower(rune(lc)) {
+		case 'y':
+			i *= 3600 * 24 * 365
+		case 'm':
+			i *= 3600 * 24 * 28
+		case 'd':
+			i *= 3600 * 24
+		case 'h':
+			i *= 3600
+		}
+
+	}
+
+	return i, err
+}
+
+// parseDuration parses a duration param, applying the decoder's duration options
+func (c *conf) parseDuration(k string, v string) (time.Duration, error) {
+
+	if c.dec.legacyDurations {
+		secs, err := parseLegacyDuration(v)
+		if err != nil {
+			return 0, fmt.Errorf("invalid value '%s' for '%s' (expected duration)", v, k)
+		}
+		if secs > math.MaxInt64/int64(time.Second) || secs < math.MinInt64/int64(time.Second) {
+			return 0, fmt.Errorf("invalid value '%s' for '%s' (out of range)", v, k)
+		}
+		return time.Duration(secs) * time.Second, nil
+	}
+
+	ns, err := parseDuration(v)
+	if err == strconv.ErrRange {
+		return 0, fmt.Errorf("invalid value '%s' for '%s' (out of range)", v, k)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("invalid value '%s' for '%s' (expected duration): %w", v, k, err)
+	}
+	if ns < 0 && !c.dec.negDurations {
+		return 0, fmt.Errorf("invalid value '%s' for '%s' (must not be negative)", v, k)
+	}
+
+	return time.Duration(ns), nil
+}
+
+// parseSeconds parses a duration param, for an integer field, in seconds
+func (c *conf) parseSeconds(k string, v string) (int64, error) {
+
+	if c.dec.legacyDurations {
+		secs, err := parseLegacyDuration(v)
+		if err != nil {
+			return 0, fmt.Errorf("invalid value '%s' for '%s' (expected duration)", v, k)
+		}
+		return secs, nil
+	}
+
+	secs, err := parseDurationSeconds(v)
+	if err == strconv.ErrRange {
+		return 0, fmt.Errorf("invalid value '%s' for '%s' (out of range)", v, k)
+	}
+	if err == errNotWholeSeconds {
+		return 0, fmt.Errorf("invalid value '%s' for '%s' (%v)", v, k, err)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("invalid value '%s' for '%s' (expected duration): %w", v, k, err)
+	}
+	if secs < 0 && !c.dec.negDurations {
+		return 0, fmt.Errorf("invalid value '%s' for '%s' (must not be negative)", v, k)
+	}
+
+	return secs, nil
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <tcp4me.com!jaw>
+// Created: 2026-Oct-16 18:35 (EDT)
+// Function: testing
+
+package acconfig
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+
+	const day = 24 * time.Hour
+
+	type testdata struct {
+		txt    string
+		dur    time.Duration
+		failed bool
+	}
+
+	tests := []testdata{
+		{"0", 0, false},
+		{"90", 90 * time.Second, false},
+		{"1.5", 1500 * time.Millisecond, false},
+		{"90s", 90 * time.Second, false},
+		{"5m", 5 * time.Minute, false},
+		{"5M", 5 * time.Minute, false},
+		{"1h30m", 90 * time.Minute, false},
+		{"1d12h", 36 * time.Hour, false},
+		{"1.5d", 36 * time.Hour, false},
+		{".5h", 30 * time.Minute, false},
+		{"2w", 14 * day, false},
+		{"6mo", 180 * day, false},
+		{"1y", 365 * day, false},
+		{"1y2mo3w4d5h6m7s", 365*day + 60*day + 21*day + 4*day + 5*time.Hour + 6*time.Minute + 7*time.Second, false},
+		{"250ms", 250 * time.Millisecond, false},
+		{"10us", 10 * time.Microsecond, false},
+		{"10µs", 10 * time.Microsecond, false},
+		{"7ns", 7, false},
+		{"+1h", time.Hour, false},
+		{"-1h", -time.Hour, false},
+		{"2562047h47m16.854775807s", math.MaxInt64, false},
+		{"-2562047h47m16.854775808s", math.MinInt64, false},
+		{"2562047h47m16.854775808s", 0, true},
+		{"293y", 0, true},
+		{"", 0, true},
+		{"-", 0, true},
+		{"h", 0, true},
+		{"1x", 0, true},
+		{"1h30", 0, true},
+		{"1hh", 0, true},
+		{"1.2.3s", 0, true},
+		{"1h-30m", 0, true},
+		{"1 h", 0, true},
+	}
+
+	for _, td := range tests {
+		ns, err := parseDuration(td.txt)
+
+		if td.failed {
+			if err == nil {
+				t.Errorf("expected to fail: '%s', got %v", td.txt, time.Duration(ns))
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("failed: '%s': %v", td.txt, err)
+			continue
+		}
+		if time.Duration(ns) != td.dur {
+			t.Errorf("'%s': expected %v, got %v", td.txt, td.dur, time.Duration(ns))
+		}
+	}
+}
+
+func TestDurations(t *testing.T) {
+
+	type stuff struct {
+		Timeout int64         `ac/convert:"duration"`
+		Retry   uint32        `ac/convert:"duration"`
+		Expire  time.Duration `ac/convert:"duration"`
+		Elapsed time.Duration
+		Offset  []int `ac/convert:"duration"`
+	}
+
+	var data stuff
+
+	err := ReadString("test", `
+timeout 1d12h
+retry   1m30s
+expire  1w
+elapsed 1h30m
+offset  10 2m 1h
+`, &data)
+
+	if err != nil {
+		t.Fatalf("failed: %v", err)
+	}
+
+	if data.Timeout != 36*3600 || data.Retry != 90 || data.Expire != 7*24*time.Hour || data.Elapsed != 90*time.Minute {
+		t.Errorf("failed: %+v", data)
+	}
+	if len(data.Offset) != 3 || data.Offset[1] != 120 {
+		t.Errorf("failed: %+v", data.Offset)
+	}
+
+	type testdata struct {
+		txt    string
+		expect string
+	}
+
+	tests := []testdata{
+		{"timeout\n", "invalid value '' for 'timeout' (expected duration): missing number"},
+		{"timeout 5x\n", "invalid value '5x' for 'timeout' (expected duration): unknown unit 'x'"},
+		{"timeout 1.5s\n", "invalid value '1.5s' for 'timeout' (not a whole number of seconds)"},
+		{"timeout -5m\n", "invalid value '-5m' for 'timeout' (must not be negative)"},
+		{"expire 300y\n", "invalid value '300y' for 'expire' (out of range)"},
+		{"retry 200y\n", "invalid value '200y' for 'retry' (out of range for uint32)"},
+		{"timeout 300000000000y\n", "invalid value '300000000000y' for 'timeout' (out of range)"},
+		{"timeout 0x10\n", "invalid value '0x10' for 'timeout' (expected duration): unknown unit 'x'"},
+		{"elapsed 1d\n", "cannot parse time.Duration for 'elapsed': time: unknown unit \"d\" in duration \"1d\""},
+	}
+
+	for _, td := range tests {
+		data = stuff{}
+		err := ReadString("test", td.txt, &data)
+
+		pe, ok := err.(*ParseError)
+		if !ok {
+			t.Errorf("expected ParseError, got %T: %v", err, err)
+			continue
+		}
+		if pe.Err.Error() != td.expect {
+			t.Errorf("expected %q, got %q", td.expect, pe.Err.Error())
+		}
+	}
+
+	// longer than a time.Duration
+	data = stuff{}
+	err = ReadString("test", "timeout 500y\n", &data)
+	if err != nil || data.Timeout != 500*365*24*3600 {
+		t.Errorf("failed: %+v %v", data, err)
+	}
+
+	// negative
+	data = stuff{}
+	dec := New(WithNegativeDurations(true))
+	err = dec.ReadString("test", "timeout -5m\nexpire -1h\n", &data)
+	if err != nil || data.Timeout != -300 || data.Expire != -time.Hour {
+		t.Errorf("failed: %+v %v", data, err)
+	}
+	err = dec.ReadString("test", "retry -5m\n", &data)
+	if err == nil {
+		t.Errorf("expected to fail: %+v", data)
+	}
+
+	// legacy
+	data = stuff{}
+	dec = New(WithLegacyDurations(true))
+	err = dec.ReadString("test", "timeout 2m\nretry 30x\nexpire 1d\n", &data)
+	if err != nil || data.Timeout != 2*28*24*3600 || data.Retry != 30 || data.Expire != 24*time.Hour {
+		t.Errorf("failed: %+v %v", data, err)
+	}
+	err = dec.ReadString("test", "timeout 0x10\n", &data)
+	if err != nil || data.Timeout != 16 {
+		t.Errorf("failed: %+v %v", data, err)
+	}
+	err = dec.ReadString("test", "timeout 1h30m\n", &data)
+	if err == nil {
+		t.Errorf("expected to fail: %+v", data)
+	}
+}
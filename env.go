@@ -0,0 +1,104 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <tcp4me.com!jaw>
+// Created: 2026-Oct-16 19:05 (EDT)
+// Function: environment variables
+
+package acconfig
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// isVarRef checks if a '$' starts a variable reference: ${...}
+// if the decoder has no lookup function, '$' is not special
+func (c *conf) isVarRef(f *bufio.Reader) bool {
+
+	if c.dec == nil || c.dec.lookupEnv == nil {
+		return false
+	}
+
+	b, err := f.Peek(1)
+	return err == nil && b[0] == '{'
+}
+
+// readVar expands a variable reference, the '$' has already been read:
+//
+//	${VAR}              the value of VAR, empty if it is not set
+//	${VAR:-default}     default if VAR is not set, or is empty
+//	${VAR:?message}     an error if VAR is not set, or is empty
+func (c *conf) readVar(f *bufio.Reader) ([]byte, error) {
+
+	var buf []byte
+	var pos = position{c.line, c.col}
+
+	// the '{'
+	if _, err := c.readByte(f); err != nil {
+		return nil, err
+	}
+
+	for {
+		ch, err := c.readByte(f)
+		if err == io.EOF || ch == '\n' {
+			return nil, c.parseError(pos, "", fmt.Errorf("unterminated variable reference"))
+		}
+		if err != nil {
+			return nil, err
+		}
+		if ch == '}' {
+			break
+		}
+		buf = append(buf, ch)
+	}
+
+	ref := string(buf)
+	name, op, arg := ref, "", ""
+
+	if i := strings.IndexByte(ref, ':'); i >= 0 {
+		name, op, arg = ref[:i], ref[i:], ref[i+1:]
+		if len(arg) == 0 || arg[0] != '-' && arg[0] != '?' {
+			return nil, c.parseError(pos, "", fmt.Errorf("invalid variable reference '${%s}'", ref))
+		}
+		op, arg = op[:2], arg[1:]
+	}
+
+	if !isVarName(name) {
+		return nil, c.parseError(pos, "", fmt.Errorf("invalid variable name '%s'", name))
+	}
+
+	val, _ := c.dec.lookupEnv(name)
+	if val != "" {
+		return []byte(val), nil
+	}
+
+	switch op {
+	case ":-":
+		return []byte(arg), nil
+	case ":?":
+		if arg == "" {
+			arg = "not set"
+		}
+		return nil, c.parseError(pos, "", fmt.Errorf("${%s}: %s", name, arg))
+	}
+
+	return nil, nil
+}
+
+func isVarName(name string) bool {
+
+	if name == "" {
+		return false
+	}
+
+	for i, ch := range name {
+		switch {
+		case ch == '_', ch >= 'a' && ch <= 'z', ch >= 'A' && ch <= 'Z':
+		case ch >= '0' && ch <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <tcp4me.com!jaw>
+// Created: 2026-Oct-16 19:30 (EDT)
+// Function: testing
+
+package acconfig
+
+import (
+	"testing"
+)
+
+func TestEnv(t *testing.T) {
+
+	type stuff struct {
+		Host   string
+		Port   int
+		URL    string
+		Greet  string
+		Raw    string
+		Name   string
+		Region string
+		Tag    []string
+	}
+
+	env := map[string]string{
+		"HOST":  "db.example.com",
+		"PORT":  "5432",
+		"EMPTY": "",
+		"WORDS": "lorem ipsum",
+	}
+
+	dec := New(WithLookupEnv(func(k string) (string, bool) {
+		v, ok := env[k]
+		return v, ok
+	}))
+
+	txt := `
+host   ${HOST}
+port   ${PORT:-80}
+url    postgres://${HOST}:${PORT}/db
+greet  "hello ${NAME:-world}\t${EMPTY:-!}"
+raw    '${HOST}'"\${HOST}"$HOST
+name   ${NAME}x
+region ${REGION:?must be set in production}
+tag    ${WORDS} ${HOST}
+`
+
+	var data stuff
+
+	err := dec.ReadString("test", txt, &data)
+
+	if err == nil {
+		t.Fatalf("expected to fail: %+v", data)
+	}
+	pe, ok := err.(*ParseError)
+	if !ok || pe.Line != 8 || pe.Col != 8 || pe.Err.Error() != "${REGION}: must be set in production" {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	env["REGION"] = "us-east"
+	data = stuff{}
+	err = dec.ReadString("test", txt, &data)
+
+	if err != nil {
+		t.Fatalf("failed: %v", err)
+	}
+
+	if data.Host != "db.example.com" || data.Port != 5432 || data.URL != "postgres://db.example.com:5432/db" {
+		t.Errorf("failed: %+v", data)
+	}
+	if data.Greet != "hello world\t!" || data.Raw != "${HOST}${HOST}$HOST" || data.Name != "x" || data.Region != "us-east" {
+		t.Errorf("failed: %+v", data)
+	}
+	// expanded values are not split
+	if len(data.Tag) != 2 || data.Tag[0] != "lorem ipsum" {
+		t.Errorf("failed: %+v", data.Tag)
+	}
+
+	fails := []string{
+		"host ${HOST\n",
+		"host ${HOST:x}\n",
+		"host ${1HOST}\n",
+		"host ${}\n",
+		"host ${EMPTY:?}\n",
+		"host \"${HOST\"\n",
+	}
+
+	for _, txt := range fails {
+		data = stuff{}
+		err := dec.ReadString("test", txt, &data)
+		if err == nil {
+			t.Errorf("expected to fail: '%s', got %+v", txt, data)
+		}
+	}
+
+	// disabled
+	data = stuff{}
+	err = New(WithLookupEnv(nil)).ReadString("test", "host ${HOST}\nraw ^a${2}$\nname \"${1x:y}\"\n", &data)
+	if err != nil || data.Host != "${HOST}" || data.Raw != "^a${2}$" || data.Name != "${1x:y}" {
+		t.Errorf("failed: %+v %v", data, err)
+	}
+
+	// default
+	t.Setenv("ACCONFIG_TEST_HOST", "gizmo")
+	data = stuff{}
+	err = ReadString("test", "host ${ACCONFIG_TEST_HOST}\n", &data)
+	if err != nil || data.Host != "gizmo" {
+		t.Errorf("failed: %+v %v", data, err)
+	}
+}
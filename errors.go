@@ -0,0 +1,205 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <tcp4me.com!jaw>
+// Created: 2026-Oct-16 11:05 (EDT)
+// Function: errors
+
+package acconfig
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ParseError describes a problem in a config file, and where it is
+type ParseError struct {
+	File     string
+	Line     int
+	Col      int
+	Key      string
+	Err      error
+	Included []Position // the include directives that led to File, innermost first
+}
+
+// ErrorList contains all of the errors found, when the Decoder is collecting errors
+type ErrorList []error
+
+// Position is a location in a config file
+type Position struct {
+	File string
+	Line int
+	Col  int
+}
+
+type position struct {
+	line int
+	col  int
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%s:%d", p.File, p.Line)
+}
+
+func (e *ParseError) Error() string {
+
+	if e.Line == 0 {
+		// not a specific line, eg. a missing param
+		return fmt.Sprintf("cannot parse file '%s': %v", e.File, e.Err)
+	}
+
+	if len(e.Included) == 0 {
+		return fmt.Sprintf("cannot parse file '%s' line %d col %d: %v", e.File, e.Line, e.Col, e.Err)
+	}
+
+	var inc strings.Builder
+	for _, p := range e.Included {
+		fmt.Fprintf(&inc, " included from %s", p)
+	}
+
+	return fmt.Sprintf("cannot parse file '%s' line %d col %d (%s): %v", e.File, e.Line, e.Col, inc.String()[1:], e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+func (l ErrorList) Error() string {
+
+	msg := make([]string, len(l))
+	for i, err := range l {
+		msg[i] = err.Error()
+	}
+	return strings.Join(msg, "\n")
+}
+
+func (l ErrorList) Unwrap() []error {
+	return l
+}
+
+func (l *ErrorList) add(err error) {
+
+	if el, ok := err.(ErrorList); ok {
+		*l = append(*l, el...)
+		return
+	}
+	*l = append(*l, err)
+}
+
+// fail records a recoverable error. if the decoder is collecting errors,
+// it is saved, and parsing continues. otherwise, it is returned
+func (c *conf) fail(err error) error {
+
+	if err == nil || !c.dec.collect {
+		return err
+	}
+	c.state.errs.add(err)
+	return nil
+}
+
+// parseError adds the file position to an error
+func (c *conf) parseError(p position, key string, err error) error {
+
+	if err == nil {
+		return nil
+	}
+	switch err.(type) {
+	case *ParseError, ErrorList:
+		// already has a position
+		return err
+	}
+
+	return &ParseError{
+		File:     c.file,
+		Line:     p.line,
+		Col:      p.col,
+		Key:      key,
+		Err:      err,
+		Included: c.included,
+	}
+}
+
+// describeBlock describes where a param was looked up, for error messages
+func describeBlock(where string, cf interface{}) string {
+
+	name := reflect.TypeOf(cf).Elem().Name()
+
+	switch {
+	case where == "" && name == "":
+		return "at top level"
+	case where == "":
+		return fmt.Sprintf("in %s", name)
+	case name == "":
+		return fmt.Sprintf("in '%s'", where)
+	default:
+		return fmt.Sprintf("in '%s' (%s)", where, name)
+	}
+}
+
+// suggest finds the closest valid name, if any are close enough
+func suggest(k string, info fieldInfo) string {
+
+	names := make([]string, 0, len(info))
+	for name := range info {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// permit roughly one typo per 3 chars
+	maxd := len(k) / 3
+	if maxd < 1 {
+		maxd = 1
+	}
+	if maxd > 3 {
+		maxd = 3
+	}
+
+	best := ""
+	for _, name := range names {
+		if d := editDistance(k, name); d <= maxd {
+			best = name
+			maxd = d - 1
+		}
+	}
+
+	return best
+}
+
+// editDistance is the edit distance between a and b,
+// counting a transposition of adjacent chars as one edit
+func editDistance(a, b string) int {
+
+	// previous two rows, and the current row
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			d := prev[j-1] + cost
+			if prev[j]+1 < d {
+				d = prev[j] + 1
+			}
+			if curr[j-1]+1 < d {
+				d = curr[j-1] + 1
+			}
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] && prev2[j-2]+1 < d {
+				d = prev2[j-2] + 1
+			}
+			curr[j] = d
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+
+	return prev[len(b)]
+}
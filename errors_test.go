@@ -0,0 +1,291 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <tcp4me.com!jaw>
+// Created: 2026-Oct-16 11:31 (EDT)
+// Function: testing
+
+package acconfig
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestErrorPosition(t *testing.T) {
+
+	type thing struct {
+		Name string
+		Size int
+	}
+	type stuff struct {
+		Name  string
+		Size  int
+		Thing []*thing
+	}
+
+	type testdata struct {
+		txt  string
+		line int
+		col  int
+		key  string
+	}
+
+	tests := []testdata{
+		{"name gizmo\nsize big\n", 2, 1, "size"},
+		{"name gizmo\n\n   # comment\n  size: big # comment\n", 4, 3, "size"},
+		{"name \"multi\nline\" # comment\nsize big\n", 3, 1, "size"},
+		{"name 'quoted' # comment 'x'\ncolor purple\n", 2, 1, "color"},
+		{"thing {\n  name gizmo\n\tsize big\n}\n", 3, 2, "size"},
+		{"name gizmo\nsize 123\nname \"unterminated\n", 3, 6, ""},
+	}
+
+	for _, td := range tests {
+		var data stuff
+		err := ReadString("test.conf", td.txt, &data)
+
+		if err == nil {
+			t.Errorf("expected to fail: '%s'", td.txt)
+			continue
+		}
+
+		var pe *ParseError
+		if !errors.As(err, &pe) {
+			t.Errorf("expected ParseError, got %T: %v", err, err)
+			continue
+		}
+
+		if pe.File != "test.conf" || pe.Line != td.line || pe.Col != td.col || pe.Key != td.key {
+			t.Errorf("expected test.conf:%d:%d %s, got %s:%d:%d %s: %v", td.line, td.col, td.key,
+				pe.File, pe.Line, pe.Col, pe.Key, err)
+		}
+	}
+}
+
+var errNotColor = errors.New("not a color")
+
+type color string
+
+func (c *color) UnmarshalString(s string) error {
+	switch s {
+	case "red", "green", "blue":
+		*c = color(s)
+		return nil
+	}
+	return errNotColor
+}
+
+func TestErrorUnwrap(t *testing.T) {
+
+	type stuff struct {
+		Color color
+	}
+
+	var data stuff
+	err := ReadString("test", "color purple\n", &data)
+
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Key != "color" {
+		t.Errorf("expected ParseError, got %T: %v", err, err)
+	}
+	if !errors.Is(err, errNotColor) {
+		t.Errorf("expected to unwrap to errNotColor, got %v", err)
+	}
+}
+
+func TestIncludeError(t *testing.T) {
+
+	type stuff struct {
+		Name string
+		Size int
+	}
+
+	fsys := fstest.MapFS{
+		"main.conf":    {Data: []byte("name gizmo\n\ninclude a.conf\n")},
+		"a.conf":       {Data: []byte("# comment\ninclude sub/b.conf\n")},
+		"sub/b.conf":   {Data: []byte("size 1\n  size big\n")},
+		"missing.conf": {Data: []byte("name gizmo\ninclude nope.conf\n")},
+	}
+
+	var data stuff
+	err := ReadFS(fsys, "main.conf", &data)
+
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected ParseError, got %T: %v", err, err)
+	}
+
+	if pe.File != "sub/b.conf" || pe.Line != 2 || pe.Col != 3 {
+		t.Errorf("wrong position: %v", err)
+	}
+	if len(pe.Included) != 2 || pe.Included[0] != (Position{"a.conf", 2, 1}) || pe.Included[1] != (Position{"main.conf", 3, 1}) {
+		t.Errorf("wrong include chain: %+v", pe.Included)
+	}
+
+	expect := "cannot parse file 'sub/b.conf' line 2 col 3 (included from a.conf:2 included from main.conf:3): invalid value"
+	if !strings.HasPrefix(err.Error(), expect) {
+		t.Errorf("wrong message: %q", err.Error())
+	}
+
+	err = ReadFS(fsys, "missing.conf", &data)
+	if !errors.As(err, &pe) || pe.File != "missing.conf" || pe.Line != 2 || pe.Key != "include" {
+		t.Errorf("expected ParseError, got %T: %v", err, err)
+	}
+}
+
+func TestCollectErrors(t *testing.T) {
+
+	type thing struct {
+		Name string
+		Size int
+	}
+	type stuff struct {
+		Name  string
+		Size  int
+		Color color
+		Thing []*thing
+		Cost  map[string]float64
+	}
+
+	fsys := fstest.MapFS{
+		"main.conf": {Data: []byte(`name gizmo
+size big
+color purple
+thing {
+    name momerath
+    size small
+}
+widget {
+    size 123
+}
+cost {
+    sugar 1.23
+    eggs  lots
+}
+include more.conf
+include missing.conf
+thing {
+    name vorpalsword
+    size 123
+}
+`)},
+		"more.conf": {Data: []byte("name gadget\ncolour red\n")},
+	}
+
+	var data stuff
+
+	err := ReadFS(fsys, "main.conf", &data)
+	if _, ok := err.(*ParseError); !ok {
+		t.Errorf("expected a single error, got %T: %v", err, err)
+	}
+
+	data = stuff{}
+	err = New(WithCollectErrors(true)).ReadFS(fsys, "main.conf", &data)
+
+	var el ErrorList
+	if !errors.As(err, &el) {
+		t.Fatalf("expected ErrorList, got %T: %v", err, err)
+	}
+
+	expect := []Position{
+		{"main.conf", 2, 1},
+		{"main.conf", 3, 1},
+		{"main.conf", 6, 5},
+		{"main.conf", 8, 1},
+		{"main.conf", 13, 5},
+		{"more.conf", 2, 1},
+		{"main.conf", 16, 1},
+	}
+
+	if len(el) != len(expect) {
+		t.Fatalf("expected %d errors, got %d: %v", len(expect), len(el), err)
+	}
+
+	for i, e := range el {
+		var pe *ParseError
+		if !errors.As(e, &pe) {
+			t.Errorf("expected ParseError, got %T: %v", e, e)
+			continue
+		}
+		if (Position{pe.File, pe.Line, pe.Col}) != expect[i] {
+			t.Errorf("error %d: expected %v:%d, got %v", i, expect[i], expect[i].Col, e)
+		}
+	}
+
+	if !errors.Is(err, errNotColor) {
+		t.Errorf("expected to unwrap to errNotColor")
+	}
+
+	// good values are still read
+	if data.Name != "gadget" || len(data.Thing) != 2 || data.Thing[1].Size != 123 || data.Cost["sugar"] != 1.23 {
+		t.Errorf("failed: got %+v", data)
+	}
+}
+
+type Thing struct {
+	Name    string
+	Timeout int
+}
+
+type Config struct {
+	Name  string
+	Param Thing
+	Thing []*Thing
+}
+
+func TestSuggest(t *testing.T) {
+
+	type testdata struct {
+		txt    string
+		expect string
+	}
+
+	tests := []testdata{
+		{"nmae gizmo\n", "invalid param 'nmae' in Config, did you mean 'name'?"},
+		{"color purple\n", "invalid param 'color' in Config"},
+		{"thing {\n}\nthing {\n  timout 10\n}\n", "invalid param 'timout' in 'thing[1]' (Thing), did you mean 'timeout'?"},
+		{"param.timout 10\n", "invalid param 'param.timout' in 'param' (Thing), did you mean 'param.timeout'?"},
+		{"parm.timeout 10\n", "invalid param 'parm.timeout' in Config, did you mean 'param.timeout'?"},
+		{"thinng {\n}\n", "invalid section 'thinng' in Config, did you mean 'thing'?"},
+		{"param {\n  thing {\n  }\n}\n", "invalid section 'thing' in 'param' (Thing)"},
+	}
+
+	for _, td := range tests {
+		var data Config
+		err := ReadString("test", td.txt, &data)
+
+		var pe *ParseError
+		if !errors.As(err, &pe) {
+			t.Errorf("expected ParseError, got %T: %v", err, err)
+			continue
+		}
+		if pe.Err.Error() != td.expect {
+			t.Errorf("expected %q, got %q", td.expect, pe.Err.Error())
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+
+	type testdata struct {
+		a, b string
+		dist int
+	}
+
+	tests := []testdata{
+		{"", "", 0},
+		{"timeout", "timeout", 0},
+		{"timout", "timeout", 1},
+		{"tiemout", "timeout", 1},
+		{"nmae", "name", 1},
+		{"tmieuot", "timeout", 2},
+		{"", "name", 4},
+		{"kitten", "sitting", 3},
+	}
+
+	for _, td := range tests {
+		if d := editDistance(td.a, td.b); d != td.dist {
+			t.Errorf("editDistance(%q, %q): expected %d, got %d", td.a, td.b, td.dist, d)
+		}
+	}
+}
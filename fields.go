@@ -0,0 +1,195 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <tcp4me.com!jaw>
+// Created: 2026-Oct-16 12:20 (EDT)
+// Function: struct field defaults, checks, and validation
+
+package acconfig
+
+import (
+	"bufio"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Defaulter is implemented by config structs that set their own defaults.
+// SetDefaults is called before the config is read, after any `ac/default` values are applied
+type Defaulter interface {
+	SetDefaults()
+}
+
+// Validator is implemented by config structs that check themselves.
+// Validate is called once the struct (or block) has been read
+type Validator interface {
+	Validate() error
+}
+
+// initStruct applies the `ac/default` values to a newly created struct,
+// and to any structs nested inside of it
+func (c *conf) initStruct(v reflect.Value, path string) error {
+
+	for _, sf := range reflect.VisibleFields(v.Type()) {
+		if !sf.IsExported() {
+			continue
+		}
+
+		fv, err := v.FieldByIndexErr(sf.Index)
+		if err != nil {
+			// inside a nil embedded pointer
+			continue
+		}
+
+		name := c.fieldName(sf)
+
+		if def, ok := c.tag(sf.Tag, "default"); ok {
+			vals, err := splitTokens(def)
+			if err != nil || len(vals) == 0 {
+				return fmt.Errorf("invalid default for '%s': '%s'", name, def)
+			}
+
+			err = c.checkAndStoreField(fv, sf.Tag, name, vals[0], vals[1:])
+			if err != nil {
+				return fmt.Errorf("invalid default for '%s': %w", name, err)
+			}
+			c.state.defaulted[joinPath(path, name)] = true
+			continue
+		}
+
+		// embedded struct fields are visible, and handled above
+		if fv.Kind() == reflect.Struct && !sf.Anonymous && !c.isScalar(fv.Type()) {
+			if err := c.initStruct(fv, joinPath(path, name)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if d, ok := v.Addr().Interface().(Defaulter); ok {
+		d.SetDefaults()
+	}
+
+	return nil
+}
+
+// allocStruct allocates and initializes the struct for a nil *struct field
+func (c *conf) allocStruct(fv reflect.Value, path string) error {
+
+	if !fv.IsNil() {
+		return nil
+	}
+
+	fv.Set(reflect.New(fv.Type().Elem()))
+	return c.initStruct(fv.Elem(), path)
+}
+
+// finishStruct checks a struct, and any structs nested inside of it,
+// once it has been fully read. all missing `ac/required` params are reported,
+// and then the struct is validated
+func (c *conf) finishStruct(v reflect.Value, path string, pos position) error {
+
+	var errs ErrorList
+	c.checkStruct(v, path, pos, &errs)
+
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return c.fail(errs[0])
+	default:
+		return c.fail(errs)
+	}
+}
+
+func (c *conf) checkStruct(v reflect.Value, path string, pos position, errs *ErrorList) {
+
+	// use the position of the block, if there was one
+	if p, ok := c.state.blockPos[path]; ok {
+		pos = p
+	}
+
+	missing := false
+
+	for _, sf := range reflect.VisibleFields(v.Type()) {
+		if !sf.IsExported() {
+			continue
+		}
+
+		fv, err := v.FieldByIndexErr(sf.Index)
+		if err != nil {
+			continue
+		}
+
+		name := c.fieldName(sf)
+		fpath := joinPath(path, name)
+
+		if _, ok := c.tag(sf.Tag, "required"); ok && !c.state.set[fpath] {
+			errs.add(c.parseError(pos, name, fmt.Errorf("missing required param '%s'", fpath)))
+			missing = true
+		}
+
+		if fv.Kind() == reflect.Pointer && !fv.IsNil() {
+			fv = fv.Elem()
+		}
+		if fv.Kind() == reflect.Struct && !sf.Anonymous && !c.isScalar(fv.Type()) {
+			c.checkStruct(fv, fpath, pos, errs)
+		}
+	}
+
+	if missing {
+		// don't bother validating an incomplete struct
+		return
+	}
+
+	if vr, ok := v.Addr().Interface().(Validator); ok {
+		if err := vr.Validate(); err != nil {
+			if path != "" {
+				err = fmt.Errorf("invalid '%s': %w", path, err)
+			}
+			errs.add(c.parseError(pos, path, err))
+		}
+	}
+}
+
+// markSet notes that a param is being set from the config file.
+// a default slice or map is replaced, not appended to
+func (c *conf) markSet(path string, fv reflect.Value) {
+
+	// "a.b.c" also sets "a" and "a.b"
+	for i := range path {
+		if path[i] == '.' {
+			c.state.set[path[:i]] = true
+		}
+	}
+	c.state.set[path] = true
+
+	if !c.state.defaulted[path] {
+		return
+	}
+
+	delete(c.state.defaulted, path)
+
+	switch fv.Kind() {
+	case reflect.Slice, reflect.Map:
+		fv.Set(reflect.Zero(fv.Type()))
+	}
+}
+
+// splitTokens splits a string into tokens, the same as a line of the config file
+func splitTokens(s string) ([]string, error) {
+
+	var res []string
+	var t = &conf{}
+	var f = bufio.NewReader(strings.NewReader(s + "\n"))
+
+	for {
+		tok, delim, err := t.readToken(f, false)
+		if err != nil {
+			return nil, err
+		}
+		if tok != "" {
+			res = append(res, tok)
+		}
+		if delim == '\n' {
+			return res, nil
+		}
+	}
+}
@@ -0,0 +1,299 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <tcp4me.com!jaw>
+// Created: 2026-Oct-16 12:44 (EDT)
+// Function: testing
+
+package acconfig
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestDefaults(t *testing.T) {
+
+	type thing struct {
+		Name string `ac/default:"unnamed"`
+		Size int    `ac/default:"8080"`
+	}
+
+	type stuff struct {
+		Name    string        `ac/default:"gizmo"`
+		Port    int           `ac/default:"8080"`
+		Timeout int64         `ac/default:"30s" ac/convert:"duration"`
+		Elapsed time.Duration `ac/default:"1m"`
+		Tag     []string      `ac/default:"lorem 'ipsum dolor'"`
+		Header  map[string]string
+		Alias   map[string]string `ac/default:"one two"`
+		Param   thing
+		Thing   []*thing
+	}
+
+	var data stuff
+
+	err := ReadString("test", "", &data)
+	if err != nil {
+		t.Fatalf("failed: %v", err)
+	}
+
+	if data.Name != "gizmo" || data.Port != 8080 || data.Timeout != 30 || data.Elapsed != time.Minute {
+		t.Errorf("failed to set defaults: %+v", data)
+	}
+	if len(data.Tag) != 2 || data.Tag[1] != "ipsum dolor" {
+		t.Errorf("failed to set default slice: %+v", data.Tag)
+	}
+	if data.Alias["one"] != "two" {
+		t.Errorf("failed to set default map: %+v", data.Alias)
+	}
+	if data.Param.Name != "unnamed" || data.Param.Size != 8080 {
+		t.Errorf("failed to set nested defaults: %+v", data.Param)
+	}
+
+	data = stuff{}
+	err = ReadString("test", `
+name  gadget
+tag   bandersnatch
+tag   jubjubtree
+alias {
+    three four
+}
+param.size 123
+thing {
+    name momerath
+}
+thing {
+    size 234
+}
+`, &data)
+
+	if err != nil {
+		t.Fatalf("failed: %v", err)
+	}
+
+	if data.Name != "gadget" || data.Port != 8080 {
+		t.Errorf("failed to override defaults: %+v", data)
+	}
+	if len(data.Tag) != 2 || data.Tag[0] != "bandersnatch" {
+		t.Errorf("failed to replace default slice: %+v", data.Tag)
+	}
+	if len(data.Alias) != 1 || data.Alias["three"] != "four" {
+		t.Errorf("failed to replace default map: %+v", data.Alias)
+	}
+	if data.Param.Name != "unnamed" || data.Param.Size != 123 {
+		t.Errorf("failed to set nested defaults: %+v", data.Param)
+	}
+	if len(data.Thing) != 2 || data.Thing[0].Size != 8080 || data.Thing[1].Name != "unnamed" || data.Thing[1].Size != 234 {
+		t.Errorf("failed to set block defaults: %+v %+v", data.Thing[0], data.Thing[1])
+	}
+}
+
+func TestBadDefault(t *testing.T) {
+
+	type stuff struct {
+		Port int `ac/default:"http"`
+	}
+
+	var data stuff
+
+	err := ReadString("test", "port 80\n", &data)
+	if err == nil {
+		t.Errorf("expected to fail: %+v", data)
+	}
+}
+
+func TestRequired(t *testing.T) {
+
+	type object struct {
+		Name string `ac/required:""`
+	}
+	type thing struct {
+		Name string `ac/required:""`
+		Size int    `ac/required:"" ac/default:"123"`
+	}
+	type stuff struct {
+		Name  string `ac/required:""`
+		Tag   []string
+		Param object
+		Thing []*thing
+	}
+
+	var data stuff
+
+	err := ReadString("test", `
+name gizmo
+param.name gadget
+thing {
+    name momerath
+    size 1
+}
+`, &data)
+
+	if err != nil {
+		t.Errorf("failed: %v", err)
+	}
+
+	data = stuff{}
+	err = ReadString("test", `
+param {
+}
+thing {
+    name momerath
+    size 1
+}
+thing {
+    name vorpalsword
+}
+`, &data)
+
+	pe, ok := err.(*ParseError)
+	if !ok || pe.Line != 8 || pe.Key != "size" {
+		t.Errorf("expected missing size, got %T: %v", err, err)
+	}
+
+	// all missing params are reported
+	data = stuff{}
+	err = ReadString("test", "name gizmo\nthing {\n}\n", &data)
+
+	el, ok := err.(ErrorList)
+	if !ok || len(el) != 2 {
+		t.Fatalf("expected 2 errors, got %T: %v", err, err)
+	}
+
+	expect := []string{
+		"cannot parse file 'test' line 2 col 1: missing required param 'thing[0].name'",
+		"cannot parse file 'test' line 2 col 1: missing required param 'thing[0].size'",
+	}
+	for i, e := range expect {
+		if el[i].Error() != e {
+			t.Errorf("expected %q, got %q", e, el[i].Error())
+		}
+	}
+
+	data = stuff{}
+	err = New(WithCollectErrors(true)).ReadString("test", `
+thing {
+    name vorpalsword
+}
+thing {
+    size 2
+}
+`, &data)
+
+	el, ok = err.(ErrorList)
+	if !ok || len(el) != 4 {
+		t.Fatalf("expected 4 errors, got %T: %v", err, err)
+	}
+
+	expect = []string{
+		"cannot parse file 'test' line 2 col 1: missing required param 'thing[0].size'",
+		"cannot parse file 'test' line 5 col 1: missing required param 'thing[1].name'",
+		"cannot parse file 'test': missing required param 'name'",
+		"cannot parse file 'test': missing required param 'param.name'",
+	}
+	for i, e := range expect {
+		if el[i].Error() != e {
+			t.Errorf("expected %q, got %q", e, el[i].Error())
+		}
+	}
+}
+
+type upstream struct {
+	Host string
+	Port int
+}
+
+func (u *upstream) SetDefaults() {
+	u.Port = 80
+}
+
+func (u *upstream) Validate() error {
+	if u.Host == "" {
+		return errors.New("host is required")
+	}
+	return nil
+}
+
+type server struct {
+	Name     string
+	Primary  upstream
+	Upstream []*upstream
+	calls    []string
+}
+
+func (s *server) SetDefaults() {
+	s.Name = "gizmo"
+	s.calls = append(s.calls, fmt.Sprintf("defaults %d", s.Primary.Port))
+}
+
+func (s *server) Validate() error {
+	s.calls = append(s.calls, fmt.Sprintf("validate %s", s.Primary.Host))
+	if len(s.Upstream) == 0 {
+		return errors.New("no upstreams")
+	}
+	return nil
+}
+
+func TestValidate(t *testing.T) {
+
+	var data server
+
+	err := ReadString("test", `
+primary.host momerath
+upstream {
+    host vorpalsword
+    port 8080
+}
+upstream {
+    host jubjubtree
+}
+`, &data)
+
+	if err != nil {
+		t.Fatalf("failed: %v", err)
+	}
+
+	if data.Name != "gizmo" || data.Primary.Port != 80 || data.Upstream[0].Port != 8080 || data.Upstream[1].Port != 80 {
+		t.Errorf("failed to set defaults: %+v", data)
+	}
+	if len(data.calls) != 2 || data.calls[0] != "defaults 80" || data.calls[1] != "validate momerath" {
+		t.Errorf("unexpected calls: %v", data.calls)
+	}
+
+	data = server{}
+	err = New(WithCollectErrors(true)).ReadString("test", `
+upstream {
+    host vorpalsword
+}
+primary {
+    port 8080
+}
+upstream {
+    port 8080
+}
+`, &data)
+
+	el, ok := err.(ErrorList)
+	if !ok || len(el) != 2 {
+		t.Fatalf("expected 2 errors, got %T: %v", err, err)
+	}
+
+	expect := []string{
+		"cannot parse file 'test' line 8 col 1: invalid 'upstream[1]': host is required",
+		"cannot parse file 'test' line 5 col 1: invalid 'primary': host is required",
+	}
+	for i, e := range expect {
+		if el[i].Error() != e {
+			t.Errorf("expected %q, got %q", e, el[i].Error())
+		}
+	}
+
+	data = server{}
+	err = ReadString("test", "primary.host momerath\n", &data)
+
+	pe, ok := err.(*ParseError)
+	if !ok || pe.Err.Error() != "no upstreams" {
+		t.Errorf("expected to fail, got %T: %v", err, err)
+	}
+}
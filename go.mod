@@ -1,3 +1,3 @@
 module github.com/jaw0/acconfig
 
-go 1.17
+go 1.20
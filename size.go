@@ -0,0 +1,128 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <tcp4me.com!jaw>
+// Created: 2026-Oct-16 16:40 (EDT)
+// Function: byte sizes
+
+package acconfig
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ByteSize is a number of bytes, written in the config file as eg. 512k, 64MB, 1.5GiB
+type ByteSize uint64
+
+// the single letter units are powers of 1024, the same as the IEC units.
+// the SI units are powers of 1000
+var sizeUnits = map[string]uint64{
+	"":  1,
+	"b": 1,
+
+	"k": 1 << 10,
+	"m": 1 << 20,
+	"g": 1 << 30,
+	"t": 1 << 40,
+	"p": 1 << 50,
+	"e": 1 << 60,
+
+	"kb": 1e3,
+	"mb": 1e6,
+	"gb": 1e9,
+	"tb": 1e12,
+	"pb": 1e15,
+	"eb": 1e18,
+
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+	"pib": 1 << 50,
+	"eib": 1 << 60,
+}
+
+func (s *ByteSize) UnmarshalText(b []byte) error {
+
+	v := string(b)
+	x, err := parseSize(v)
+	if err == strconv.ErrRange {
+		return fmt.Errorf("size '%s' out of range", v)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid size '%s'", v)
+	}
+
+	*s = ByteSize(x)
+	return nil
+}
+
+// parseSize parses a size, eg. 512k, 64MB, 1.5GiB.
+// strconv.ErrRange is returned if it does not fit in a uint64
+func parseSize(v string) (uint64, error) {
+
+	num, unit := v, ""
+	if i := strings.IndexFunc(v, unicode.IsLetter); i >= 0 {
+		num, unit = v[:i], v[i:]
+	}
+
+	mult, ok := sizeUnits[strings.ToLower(unit)]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit '%s'", unit)
+	}
+
+	return mulDecimal(num, mult)
+}
+
+// mulDecimal multiplies a decimal number, eg. 1.5, by mult.
+// strconv.ErrRange is returned if the result does not fit in a uint64
+func mulDecimal(num string, mult uint64) (uint64, error) {
+
+	whole, frac, _ := strings.Cut(num, ".")
+	if whole == "" && frac == "" {
+		return 0, fmt.Errorf("missing number")
+	}
+
+	var size uint64
+	if whole != "" {
+		w, err := strconv.ParseUint(whole, 10, 64)
+		if err != nil {
+			return 0, err.(*strconv.NumError).Err
+		}
+		hi, lo := bits.Mul64(w, mult)
+		if hi != 0 {
+			return 0, strconv.ErrRange
+		}
+		size = lo
+	}
+
+	if frac != "" {
+		if strings.TrimLeft(frac, "0123456789") != "" {
+			return 0, strconv.ErrSyntax
+		}
+		// the rest are too small to matter
+		if len(frac) > 18 {
+			frac = frac[:18]
+		}
+
+		f, _ := strconv.ParseUint(frac, 10, 64)
+		scale := uint64(1)
+		for range frac {
+			scale *= 10
+		}
+
+		// f * mult / scale, which is less than mult
+		hi, lo := bits.Mul64(f, mult)
+		q, _ := bits.Div64(hi, lo, scale)
+
+		var carry uint64
+		size, carry = bits.Add64(size, q, 0)
+		if carry != 0 {
+			return 0, strconv.ErrRange
+		}
+	}
+
+	return size, nil
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <tcp4me.com!jaw>
+// Created: 2026-Oct-16 17:02 (EDT)
+// Function: testing
+
+package acconfig
+
+import (
+	"testing"
+)
+
+func TestParseSize(t *testing.T) {
+
+	type testdata struct {
+		txt    string
+		size   uint64
+		failed bool
+	}
+
+	tests := []testdata{
+		{"0", 0, false},
+		{"1234", 1234, false},
+		{"100b", 100, false},
+		{"512k", 512 << 10, false},
+		{"512K", 512 << 10, false},
+		{"10G", 10 << 30, false},
+		{"64MB", 64e6, false},
+		{"64mb", 64e6, false},
+		{"1.5GiB", 3 << 29, false},
+		{".5k", 512, false},
+		{"2.k", 2048, false},
+		{"1.0000000000000000001e", 1 << 60, false},
+		{"15e", 15 << 60, false},
+		{"18446744073709551615", 18446744073709551615, false},
+		{"18.4eb", 184e17, false},
+		{"16e", 0, true},
+		{"18.5eb", 0, true},
+		{"18446744073709551616", 0, true},
+		{"", 0, true},
+		{"k", 0, true},
+		{".", 0, true},
+		{"10X", 0, true},
+		{"10 MB", 0, true},
+		{"-1k", 0, true},
+		{"1.2.3", 0, true},
+		{"1e3", 0, true},
+	}
+
+	for _, td := range tests {
+		size, err := parseSize(td.txt)
+
+		if td.failed {
+			if err == nil {
+				t.Errorf("expected to fail: '%s', got %d", td.txt, size)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("failed: '%s': %v", td.txt, err)
+			continue
+		}
+		if size != td.size {
+			t.Errorf("'%s': expected %d, got %d", td.txt, td.size, size)
+		}
+	}
+}
+
+func TestByteSize(t *testing.T) {
+
+	type stuff struct {
+		Buffer  ByteSize
+		Cache   int64  `ac/convert:"size" ac/max:"1G"`
+		Small   uint16 `ac/convert:"size"`
+		Limit   int    `ac/convert:"size" ac/default:"4k"`
+		Chunk   []ByteSize
+		Quota   map[string]int64 `ac/convert:"size"`
+		Largest ByteSize         `ac/min:"1MB"`
+	}
+
+	var data stuff
+
+	err := ReadString("test", `
+buffer  64MB
+cache   1.5MiB
+small   63k
+chunk   4k 8k 1.5k
+quota {
+    alice 10G
+    bob   512M
+}
+largest 2MB
+`, &data)
+
+	if err != nil {
+		t.Fatalf("failed: %v", err)
+	}
+
+	if data.Buffer != 64e6 || data.Cache != 3<<19 || data.Small != 63<<10 || data.Limit != 4096 || data.Largest != 2e6 {
+		t.Errorf("failed: %+v", data)
+	}
+	if len(data.Chunk) != 3 || data.Chunk[2] != 1536 {
+		t.Errorf("failed: %+v", data.Chunk)
+	}
+	if data.Quota["alice"] != 10<<30 || data.Quota["bob"] != 512<<20 {
+		t.Errorf("failed: %+v", data.Quota)
+	}
+
+	type testdata struct {
+		txt    string
+		expect string
+	}
+
+	tests := []testdata{
+		{"buffer 64XB\n", "cannot parse *acconfig.ByteSize for 'buffer': invalid size '64XB'"},
+		{"buffer 17e\n", "cannot parse *acconfig.ByteSize for 'buffer': size '17e' out of range"},
+		{"cache 8e\n", "invalid value '8e' for 'cache' (out of range)"},
+		{"cache 2G\n", "invalid value '2G' for 'cache': must be at most 1G (ac/max)"},
+		{"small 64k\n", "invalid value '64k' for 'small' (out of range for uint16)"},
+		{"limit lots\n", "invalid value 'lots' for 'limit' (expected size)"},
+		{"quota {\n    carol 10Q\n}\n", "invalid value '10Q' for 'quota[carol]' (expected size)"},
+		{"largest 100k\n", "invalid value '100k' for 'largest': must be at least 1MB (ac/min)"},
+	}
+
+	for _, td := range tests {
+		data = stuff{}
+		err := ReadString("test", td.txt, &data)
+
+		pe, ok := err.(*ParseError)
+		if !ok {
+			t.Errorf("expected ParseError, got %T: %v", err, err)
+			continue
+		}
+		if pe.Err.Error() != td.expect {
+			t.Errorf("expected %q, got %q", td.expect, pe.Err.Error())
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <tcp4me.com!jaw>
+// Created: 2026-Oct-16 16:05 (EDT)
+// Function: builtin types
+
+package acconfig
+
+import (
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"reflect"
+)
+
+// builtinType parses a standard library type that does not unmarshal itself
+type builtinType struct {
+	expect string // for error messages
+	parse  func(string) (interface{}, error)
+}
+
+var builtinTypes = map[reflect.Type]builtinType{
+	reflect.TypeOf(url.URL{}):          {"URL", parseURL},
+	reflect.TypeOf(&url.URL{}):         {"URL", parseURL},
+	reflect.TypeOf(net.IPNet{}):        {"CIDR", parseCIDR},
+	reflect.TypeOf(&net.IPNet{}):       {"CIDR", parseCIDR},
+	reflect.TypeOf(net.HardwareAddr{}): {"MAC address", parseMAC},
+	reflect.TypeOf(mail.Address{}):     {"email address", parseEmail},
+	reflect.TypeOf(&mail.Address{}):    {"email address", parseEmail},
+}
+
+// storeBuiltin stores a value of a builtin type. *T is set to a newly allocated value
+func (c *conf) storeBuiltin(cfv reflect.Value, bt builtinType, k string, v string) error {
+
+	x, err := bt.parse(v)
+	if err != nil {
+		return fmt.Errorf("invalid value '%s' for '%s' (expected %s): %w", v, k, bt.expect, err)
+	}
+
+	// the parse functions all return a *T, or a slice
+	xv := reflect.ValueOf(x)
+	if cfv.Kind() != reflect.Pointer && xv.Kind() == reflect.Pointer {
+		xv = xv.Elem()
+	}
+	cfv.Set(xv)
+	return nil
+}
+
+func parseURL(v string) (interface{}, error) {
+	return url.Parse(v)
+}
+
+func parseCIDR(v string) (interface{}, error) {
+	_, n, err := net.ParseCIDR(v)
+	return n, err
+}
+
+func parseMAC(v string) (interface{}, error) {
+	return net.ParseMAC(v)
+}
+
+func parseEmail(v string) (interface{}, error) {
+	return mail.ParseAddress(v)
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <tcp4me.com!jaw>
+// Created: 2026-Oct-16 16:20 (EDT)
+// Function: testing
+
+package acconfig
+
+import (
+	"errors"
+	"net"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestNetworkTypes(t *testing.T) {
+
+	type stuff struct {
+		IP       net.IP
+		Addr     netip.Addr
+		Prefix   netip.Prefix
+		Listen   netip.AddrPort
+		Upstream *url.URL
+		Home     url.URL
+		Allow    []*net.IPNet
+		Deny     []net.IPNet
+		Net      net.IPNet
+		MAC      net.HardwareAddr
+		Gateways []net.HardwareAddr
+		Admin    mail.Address
+		Notify   []*mail.Address
+		Backend  map[string]*url.URL
+		Subnet   map[string]net.IPNet
+	}
+
+	var data stuff
+
+	err := ReadString("test", `
+ip       10.1.2.3
+addr     ::1
+prefix   10.0.0.0/8
+listen   "[::1]:8080"
+upstream https://api.example.com/v1?x=1
+home     http://example.com/
+allow    10.0.0.0/8 192.168.0.0/16
+deny     0.0.0.0/0
+net      172.16.1.2/12
+mac      00:11:22:33:44:55
+gateways 00:11:22:33:44:55 66:77:88:99:aa:bb
+admin    "Jeff <jaw@example.com>"
+notify   ops@example.com dev@example.com
+backend {
+    primary   http://10.1.2.3:8080/
+    secondary http://10.1.2.4:8080/
+}
+subnet {
+    office 10.1.0.0/16
+}
+`, &data)
+
+	if err != nil {
+		t.Fatalf("failed: %v", err)
+	}
+
+	if data.IP.String() != "10.1.2.3" || data.Addr.String() != "::1" || data.Prefix.Bits() != 8 || data.Listen.Port() != 8080 {
+		t.Errorf("failed: %+v", data)
+	}
+	if data.Upstream == nil || data.Upstream.Host != "api.example.com" || data.Upstream.Query().Get("x") != "1" || data.Home.Scheme != "http" {
+		t.Errorf("failed: %+v %+v", data.Upstream, data.Home)
+	}
+	if len(data.Allow) != 2 || data.Allow[1].String() != "192.168.0.0/16" || len(data.Deny) != 1 || !data.Deny[0].Contains(net.ParseIP("1.2.3.4")) {
+		t.Errorf("failed: %+v %+v", data.Allow, data.Deny)
+	}
+	if data.Net.String() != "172.16.0.0/12" {
+		t.Errorf("failed: %+v", data.Net)
+	}
+	if data.MAC.String() != "00:11:22:33:44:55" || len(data.Gateways) != 2 || data.Gateways[1].String() != "66:77:88:99:aa:bb" {
+		t.Errorf("failed: %+v %+v", data.MAC, data.Gateways)
+	}
+	if data.Admin.Name != "Jeff" || data.Admin.Address != "jaw@example.com" || len(data.Notify) != 2 || data.Notify[1].Address != "dev@example.com" {
+		t.Errorf("failed: %+v %+v", data.Admin, data.Notify)
+	}
+	if len(data.Backend) != 2 || data.Backend["secondary"].Host != "10.1.2.4:8080" {
+		t.Errorf("failed: %+v", data.Backend)
+	}
+	if n := data.Subnet["office"]; n.String() != "10.1.0.0/16" {
+		t.Errorf("failed: %+v", data.Subnet)
+	}
+
+	type testdata struct {
+		txt    string
+		expect string
+	}
+
+	tests := []testdata{
+		{"upstream http://[::1\n", "invalid value 'http://[::1' for 'upstream' (expected URL)"},
+		{"allow 10.0.0.0/8 10.0.0.0/33\n", "invalid value '10.0.0.0/33' for 'allow' (expected CIDR)"},
+		{"mac 00:11:22\n", "invalid value '00:11:22' for 'mac' (expected MAC address)"},
+		{"notify jaw@\n", "invalid value 'jaw@' for 'notify' (expected email address)"},
+		{"subnet {\n    office 10.1.0.0\n}\n", "invalid value '10.1.0.0' for 'subnet[office]' (expected CIDR)"},
+	}
+
+	for _, td := range tests {
+		data = stuff{}
+		err := ReadString("test", td.txt, &data)
+
+		var pe *ParseError
+		if !errors.As(err, &pe) {
+			t.Errorf("expected ParseError, got %T: %v", err, err)
+			continue
+		}
+		if !strings.HasPrefix(pe.Err.Error(), td.expect) {
+			t.Errorf("expected %q, got %q", td.expect, pe.Err.Error())
+		}
+	}
+}